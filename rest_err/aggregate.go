@@ -0,0 +1,127 @@
+package rest_err
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Aggregator collects the failures of several independent checks into a single RestErr.
+// The zero value is ready to use
+type Aggregator struct {
+	errs []error
+}
+
+// Add records err, nil errors are ignored
+func (a *Aggregator) Add(err error) {
+	if err != nil {
+		a.errs = append(a.errs, err)
+	}
+}
+
+// Len returns the number of recorded errors
+func (a *Aggregator) Len() int {
+	return len(a.errs)
+}
+
+// Err returns the aggregated RestErr, or nil when no error was recorded
+func (a *Aggregator) Err() *RestErr {
+	return Aggregate(a.errs...)
+}
+
+// multiError keeps every aggregated error reachable by errors.Is and errors.As
+type multiError []error
+
+func (m multiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the aggregated errors
+func (m multiError) Unwrap() []error {
+	return m
+}
+
+// Aggregate combines errs into a single RestErr.
+// Nil errors are ignored and joined errors are flattened. A single error is converted with
+// NewRestErrFromError, otherwise the causes of every error are merged (errors without causes
+// contribute their message) and the status is chosen by precedence:
+//
+//   - every error sharing the same status results in that status
+//   - otherwise any 5xx (or non RestErr error) results in 500 Internal Server Error
+//   - otherwise, with mixed 4xx statuses, the result is 400 Bad Request
+//
+// The original errors stay reachable through the Wrapped error's Unwrap() []error
+func Aggregate(errs ...error) *RestErr {
+	flat := flattenErrors(errs)
+	switch len(flat) {
+	case 0:
+		return nil
+	case 1:
+		return NewRestErrFromError(flat[0])
+	}
+
+	var causes []Causes
+	codes := make([]int, 0, len(flat))
+	for _, err := range flat {
+		restErr := NewRestErrFromError(err)
+		codes = append(codes, restErr.Code)
+
+		if len(restErr.Causes) == 0 {
+			causes = appendCause(causes, Causes{Message: restErr.Message})
+			continue
+		}
+		for _, c := range restErr.Causes {
+			causes = appendCause(causes, c)
+		}
+	}
+
+	code := aggregateCode(codes)
+	restErr := NewRestErr(fmt.Sprintf("%d errors occurred", len(flat)), errText(code), code, causes)
+	restErr.format = "%d errors occurred"
+	return restErr.WithCause(multiError(flat))
+}
+
+func flattenErrors(errs []error) []error {
+	var flat []error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if _, ok := err.(*RestErr); !ok {
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				flat = append(flat, flattenErrors(joined.Unwrap())...)
+				continue
+			}
+		}
+		flat = append(flat, err)
+	}
+	return flat
+}
+
+func appendCause(causes []Causes, c Causes) []Causes {
+	if slices.Contains(causes, c) {
+		return causes
+	}
+	return append(causes, c)
+}
+
+func aggregateCode(codes []int) int {
+	uniform, server := true, false
+	for _, code := range codes {
+		uniform = uniform && code == codes[0]
+		server = server || code >= 500
+	}
+
+	switch {
+	case uniform:
+		return codes[0]
+	case server:
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,148 @@
+package rest_err
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	t.Run("no errors", func(t *testing.T) {
+		if err := Aggregate(nil, nil); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+	})
+
+	t.Run("single error", func(t *testing.T) {
+		notFound := NewNotFoundError("user not found")
+		if err := Aggregate(nil, notFound); err != notFound {
+			t.Errorf("Expected the single RestErr to be returned, got %v", err)
+		}
+	})
+
+	t.Run("status precedence", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			errs     []error
+			expected int
+		}{
+			{"uniform 4xx", []error{NewNotFoundError("a"), NewNotFoundError("b")}, http.StatusNotFound},
+			{"uniform 5xx", []error{NewServiceUnavailableError("a"), NewServiceUnavailableError("b")}, http.StatusServiceUnavailable},
+			{"mixed 4xx", []error{NewNotFoundError("a"), NewConflictError("b")}, http.StatusBadRequest},
+			{"any 5xx", []error{NewNotFoundError("a"), NewBadGatewayError("b")}, http.StatusInternalServerError},
+			{"plain error", []error{NewNotFoundError("a"), errors.New("boom")}, http.StatusInternalServerError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := Aggregate(tt.errs...)
+				if err.Code != tt.expected {
+					t.Errorf("Expected code %d, got %d", tt.expected, err.Code)
+				}
+				if err.Err != errText(tt.expected) {
+					t.Errorf("Expected error '%s', got '%s'", errText(tt.expected), err.Err)
+				}
+			})
+		}
+	})
+
+	t.Run("merges causes", func(t *testing.T) {
+		validation := NewBadRequestValidationError("invalid", []Causes{
+			{Field: "email", Message: "is required"},
+			{Field: "name", Message: "is required"},
+		})
+		duplicate := NewBadRequestValidationError("invalid", []Causes{{Field: "email", Message: "is required"}})
+		plain := NewBadRequestError("limit is too high")
+
+		err := Aggregate(validation, duplicate, plain)
+		expected := []Causes{
+			{Field: "email", Message: "is required"},
+			{Field: "name", Message: "is required"},
+			{Message: "limit is too high"},
+		}
+		if len(err.Causes) != len(expected) {
+			t.Fatalf("Expected %d causes, got %+v", len(expected), err.Causes)
+		}
+		for i, c := range expected {
+			if err.Causes[i] != c {
+				t.Errorf("Expected cause %+v, got %+v", c, err.Causes[i])
+			}
+		}
+	})
+
+	t.Run("originals reachable", func(t *testing.T) {
+		base := errors.New("database down")
+		notFound := NewNotFoundError("user not found")
+
+		err := Aggregate(notFound, base)
+		if !errors.Is(err, base) {
+			t.Error("Expected errors.Is to find the plain error")
+		}
+
+		unwrapper, ok := err.Wrapped.(interface{ Unwrap() []error })
+		if !ok {
+			t.Fatal("Expected Wrapped to implement Unwrap() []error")
+		}
+		if len(unwrapper.Unwrap()) != 2 {
+			t.Errorf("Expected 2 unwrapped errors, got %d", len(unwrapper.Unwrap()))
+		}
+	})
+
+	t.Run("flattens joined errors", func(t *testing.T) {
+		err := Aggregate(errors.Join(NewNotFoundError("a"), NewNotFoundError("b")), NewNotFoundError("c"))
+		if err.Message != "3 errors occurred" {
+			t.Errorf("Expected 3 errors, got '%s'", err.Message)
+		}
+	})
+}
+
+func TestAggregator(t *testing.T) {
+	var agg Aggregator
+	if agg.Err() != nil {
+		t.Error("Expected nil for empty aggregator")
+	}
+
+	agg.Add(nil)
+	agg.Add(NewConflictError("duplicate email"))
+	agg.Add(NewUnprocessableEntityError("invalid", []Causes{{Field: "age", Message: "must be positive"}}))
+
+	if agg.Len() != 2 {
+		t.Errorf("Expected 2 errors, got %d", agg.Len())
+	}
+	err := agg.Err()
+	if err.Code != http.StatusBadRequest {
+		t.Errorf("Expected code 400, got %d", err.Code)
+	}
+	if len(err.Causes) != 2 {
+		t.Errorf("Expected 2 causes, got %d", len(err.Causes))
+	}
+}
+
+func TestNewRestErrFromError_Joined(t *testing.T) {
+	err := NewRestErrFromError(fmt.Errorf("checks failed: %w, %w", NewNotFoundError("a"), NewForbiddenError("b")))
+	if err.Code != http.StatusBadRequest {
+		t.Errorf("Expected code 400, got %d", err.Code)
+	}
+	if len(err.Causes) != 2 {
+		t.Errorf("Expected 2 causes, got %d", len(err.Causes))
+	}
+}
+
+type emptyJoinError struct{}
+
+func (emptyJoinError) Error() string   { return "nothing joined" }
+func (emptyJoinError) Unwrap() []error { return []error{nil} }
+
+func TestNewRestErrFromError_EmptyJoined(t *testing.T) {
+	err := NewRestErrFromError(emptyJoinError{})
+	if err == nil {
+		t.Fatal("Expected a RestErr for a non-nil error")
+	}
+	if err.Code != http.StatusInternalServerError {
+		t.Errorf("Expected code 500, got %d", err.Code)
+	}
+	if _, ok := err.Wrapped.(emptyJoinError); !ok {
+		t.Errorf("Expected the original error to be wrapped, got %v", err.Wrapped)
+	}
+}
@@ -0,0 +1,129 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// BatchMode decides the overall status of a BatchResult
+type BatchMode int
+
+const (
+	// BatchMultiStatus responds 207 Multi-Status when any item failed and 200 OK otherwise
+	BatchMultiStatus BatchMode = iota
+	// BatchAlwaysOK always responds 200 OK, clients inspect each item
+	BatchAlwaysOK
+	// BatchFailOnError responds with the aggregated failure status (see Aggregate) when any item failed
+	BatchFailOnError
+)
+
+// BatchItem is the outcome of a single item of a bulk request
+type BatchItem struct {
+	Index  int      `json:"index" example:"0"`    // Position of the item in the request
+	Status int      `json:"status" example:"201"` // HTTP status of this item
+	Data   any      `json:"data,omitempty"`       // Result of a successful item
+	Error  *RestErr `json:"error,omitempty"`      // Failure of this item
+}
+
+// BatchResult records per-item successes and failures of a bulk request.
+// It is safe for concurrent use, so items can be processed in parallel
+type BatchResult struct {
+	mode  BatchMode
+	mu    sync.Mutex
+	items map[int]BatchItem
+}
+
+// NewBatchResult returns an empty BatchResult using mode to decide the overall status
+func NewBatchResult(mode BatchMode) *BatchResult {
+	return &BatchResult{mode: mode, items: make(map[int]BatchItem)}
+}
+
+// Success records a successful item, replacing any earlier outcome for index
+func (b *BatchResult) Success(index, status int, data any) {
+	b.set(BatchItem{Index: index, Status: status, Data: data})
+}
+
+// Failure records a failed item, err is converted with NewRestErrFromError
+func (b *BatchResult) Failure(index int, err error) {
+	restErr := NewRestErrFromError(err)
+	if restErr == nil {
+		return
+	}
+	b.set(BatchItem{Index: index, Status: restErr.Code, Error: restErr})
+}
+
+func (b *BatchResult) set(item BatchItem) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.items[item.Index] = item
+}
+
+// Items returns the recorded items ordered by index
+func (b *BatchResult) Items() []BatchItem {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	items := make([]BatchItem, 0, len(b.items))
+	for _, item := range b.items {
+		items = append(items, item)
+	}
+	slices.SortFunc(items, func(a, b BatchItem) int { return a.Index - b.Index })
+	return items
+}
+
+// Status returns the overall HTTP status according to the BatchMode
+func (b *BatchResult) Status() int {
+	return b.status(b.Items())
+}
+
+func (b *BatchResult) status(items []BatchItem) int {
+	var failures []int
+	for _, item := range items {
+		if item.Error != nil {
+			failures = append(failures, item.Status)
+		}
+	}
+
+	if len(failures) == 0 || b.mode == BatchAlwaysOK {
+		return http.StatusOK
+	}
+	if b.mode == BatchFailOnError {
+		return aggregateCode(failures)
+	}
+	return http.StatusMultiStatus
+}
+
+type batchBody struct {
+	Status    int         `json:"status"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	Items     []BatchItem `json:"items"`
+}
+
+func (b *BatchResult) body() batchBody {
+	items := b.Items()
+	body := batchBody{Status: b.status(items), Items: items}
+	for _, item := range items {
+		if item.Error != nil {
+			body.Failed++
+		} else {
+			body.Succeeded++
+		}
+	}
+	return body
+}
+
+// MarshalJSON encodes the batch as {"status", "succeeded", "failed", "items": [...]}
+func (b *BatchResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.body())
+}
+
+// Write writes the batch to w as JSON using the overall status
+func (b *BatchResult) Write(w http.ResponseWriter) error {
+	body := b.body()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(body.Status)
+	return json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,121 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestBatchResult_Status(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     BatchMode
+		failures []error
+		expected int
+	}{
+		{"multi status without failures", BatchMultiStatus, nil, http.StatusOK},
+		{"multi status with failures", BatchMultiStatus, []error{NewNotFoundError("missing")}, http.StatusMultiStatus},
+		{"always ok with failures", BatchAlwaysOK, []error{NewNotFoundError("missing")}, http.StatusOK},
+		{"fail on error uniform", BatchFailOnError, []error{NewNotFoundError("a"), NewNotFoundError("b")}, http.StatusNotFound},
+		{"fail on error mixed", BatchFailOnError, []error{NewNotFoundError("a"), NewConflictError("b")}, http.StatusBadRequest},
+		{"fail on error server", BatchFailOnError, []error{NewNotFoundError("a"), errors.New("boom")}, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := NewBatchResult(tt.mode)
+			batch.Success(0, http.StatusCreated, map[string]string{"id": "a"})
+			for i, err := range tt.failures {
+				batch.Failure(i+1, err)
+			}
+			if got := batch.Status(); got != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBatchResult_Items(t *testing.T) {
+	batch := NewBatchResult(BatchMultiStatus)
+
+	var wg sync.WaitGroup
+	for i := 9; i >= 0; i-- {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				batch.Success(i, http.StatusOK, i)
+			} else {
+				batch.Failure(i, NewConflictError("item %d already exists", i))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	items := batch.Items()
+	if len(items) != 10 {
+		t.Fatalf("Expected 10 items, got %d", len(items))
+	}
+	for i, item := range items {
+		if item.Index != i {
+			t.Errorf("Expected items ordered by index, got %d at position %d", item.Index, i)
+		}
+	}
+	if items[1].Status != http.StatusConflict || items[1].Error == nil {
+		t.Errorf("Expected item 1 to be a conflict, got %+v", items[1])
+	}
+
+	batch.Success(1, http.StatusOK, "retried")
+	if batch.Items()[1].Error != nil {
+		t.Error("Expected a later outcome to replace the earlier one")
+	}
+}
+
+func TestBatchResult_Write(t *testing.T) {
+	batch := NewBatchResult(BatchMultiStatus)
+	batch.Success(0, http.StatusCreated, map[string]string{"id": "a"})
+	batch.Failure(1, NewBadRequestValidationError("invalid", []Causes{{Field: "sku", Message: "is required"}}))
+
+	rec := httptest.NewRecorder()
+	if err := batch.Write(rec); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if rec.Code != http.StatusMultiStatus {
+		t.Errorf("Expected status 207, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got '%s'", ct)
+	}
+
+	var body struct {
+		Status    int `json:"status"`
+		Succeeded int `json:"succeeded"`
+		Failed    int `json:"failed"`
+		Items     []struct {
+			Index  int             `json:"index"`
+			Status int             `json:"status"`
+			Data   json.RawMessage `json:"data"`
+			Error  *RestErr        `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Unexpected error decoding body: %v", err)
+	}
+
+	if body.Status != http.StatusMultiStatus || body.Succeeded != 1 || body.Failed != 1 {
+		t.Errorf("Unexpected summary %+v", body)
+	}
+	if len(body.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(body.Items))
+	}
+	if body.Items[0].Status != http.StatusCreated || string(body.Items[0].Data) != `{"id":"a"}` {
+		t.Errorf("Unexpected success item %+v", body.Items[0])
+	}
+	if body.Items[1].Error == nil || body.Items[1].Error.Causes[0].Field != "sku" {
+		t.Errorf("Unexpected failure item %+v", body.Items[1])
+	}
+}
@@ -0,0 +1,182 @@
+package rest_err
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Binder reads typed query, path and header parameters from a request,
+// collecting every parse failure instead of stopping at the first one.
+//
+//	b := rest_err.NewBinder(r)
+//	id := b.Path("id").Required().Int(0)
+//	limit := b.Query("limit").Int(20)
+//	if err := b.Err(); err != nil {
+//		// 400 with one cause per invalid parameter
+//	}
+type Binder struct {
+	r      *http.Request
+	query  url.Values
+	causes []Causes
+}
+
+// Param is a single named parameter bound from a Binder
+type Param struct {
+	b        *Binder
+	name     string
+	location string
+	raw      string
+	required bool
+}
+
+// NewBinder returns a Binder reading from r
+func NewBinder(r *http.Request) *Binder {
+	return &Binder{r: r, query: r.URL.Query()}
+}
+
+// Query returns the URL query parameter name
+func (b *Binder) Query(name string) Param {
+	return Param{b: b, name: name, location: LocationQuery, raw: b.query.Get(name)}
+}
+
+// Path returns the path wildcard name as matched by http.ServeMux
+func (b *Binder) Path(name string) Param {
+	return Param{b: b, name: name, location: LocationPath, raw: b.r.PathValue(name)}
+}
+
+// Header returns the request header name
+func (b *Binder) Header(name string) Param {
+	return Param{b: b, name: name, location: LocationHeader, raw: b.r.Header.Get(name)}
+}
+
+// Err returns a 400 RestErr with one cause per invalid parameter, or nil when every parameter was valid
+func (b *Binder) Err() *RestErr {
+	if len(b.causes) == 0 {
+		return nil
+	}
+	return NewBadRequestValidationError("invalid request parameters", slices.Clone(b.causes))
+}
+
+// Required marks the parameter as mandatory, a missing or empty value is reported as a cause
+func (p Param) Required() Param {
+	p.required = true
+	return p
+}
+
+// String returns the raw value, or def when absent
+func (p Param) String(def string) string {
+	if !p.check() {
+		return def
+	}
+	return p.raw
+}
+
+// Int parses the value as a base 10 int
+func (p Param) Int(def int) int {
+	if !p.check() {
+		return def
+	}
+	n, err := strconv.Atoi(p.raw)
+	if err != nil {
+		p.fail("must be an integer")
+		return def
+	}
+	return n
+}
+
+// Int64 parses the value as a base 10 int64
+func (p Param) Int64(def int64) int64 {
+	if !p.check() {
+		return def
+	}
+	n, err := strconv.ParseInt(p.raw, 10, 64)
+	if err != nil {
+		p.fail("must be an integer")
+		return def
+	}
+	return n
+}
+
+// Float64 parses the value as a float64
+func (p Param) Float64(def float64) float64 {
+	if !p.check() {
+		return def
+	}
+	f, err := strconv.ParseFloat(p.raw, 64)
+	if err != nil {
+		p.fail("must be a number")
+		return def
+	}
+	return f
+}
+
+// Bool parses the value with strconv.ParseBool
+func (p Param) Bool(def bool) bool {
+	if !p.check() {
+		return def
+	}
+	v, err := strconv.ParseBool(p.raw)
+	if err != nil {
+		p.fail("must be a boolean")
+		return def
+	}
+	return v
+}
+
+// Time parses the value using layout, e.g. time.RFC3339
+func (p Param) Time(layout string, def time.Time) time.Time {
+	if !p.check() {
+		return def
+	}
+	t, err := time.Parse(layout, p.raw)
+	if err != nil {
+		p.fail(fmt.Sprintf("must be a time in the format %s", layout))
+		return def
+	}
+	return t
+}
+
+// Duration parses the value with time.ParseDuration
+func (p Param) Duration(def time.Duration) time.Duration {
+	if !p.check() {
+		return def
+	}
+	d, err := time.ParseDuration(p.raw)
+	if err != nil {
+		p.fail("must be a duration such as 30s or 5m")
+		return def
+	}
+	return d
+}
+
+// Enum returns the value when it is one of allowed
+func (p Param) Enum(def string, allowed ...string) string {
+	if !p.check() {
+		return def
+	}
+	if !slices.Contains(allowed, p.raw) {
+		p.fail(fmt.Sprintf("must be one of [%s]", strings.Join(allowed, ", ")))
+		return def
+	}
+	return p.raw
+}
+
+// check reports whether there is a value to parse, recording a cause for missing required parameters
+func (p Param) check() bool {
+	if p.raw != "" {
+		return true
+	}
+	if p.required {
+		p.fail("is required")
+	}
+	return false
+}
+
+func (p Param) fail(message string) {
+	p.b.causes = append(p.b.causes, Causes{Field: p.name, Message: message, Location: p.location})
+}
@@ -0,0 +1,89 @@
+package rest_err
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBinder(t *testing.T) {
+	t.Run("valid parameters", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/42?limit=10&active=true&since=2024-01-02T03:04:05Z&sort=desc&timeout=5s&ratio=0.5", nil)
+		req.SetPathValue("id", "42")
+		req.Header.Set("X-Tenant", "acme")
+
+		b := NewBinder(req)
+		id := b.Path("id").Required().Int64(0)
+		limit := b.Query("limit").Int(20)
+		active := b.Query("active").Bool(false)
+		since := b.Query("since").Time(time.RFC3339, time.Time{})
+		sort := b.Query("sort").Enum("asc", "asc", "desc")
+		timeout := b.Query("timeout").Duration(time.Second)
+		ratio := b.Query("ratio").Float64(1)
+		tenant := b.Header("X-Tenant").Required().String("")
+
+		if err := b.Err(); err != nil {
+			t.Fatalf("Expected no error, got %v (causes %+v)", err, err.Causes)
+		}
+		if id != 42 || limit != 10 || !active || sort != "desc" || timeout != 5*time.Second || ratio != 0.5 || tenant != "acme" {
+			t.Errorf("Unexpected bound values: %d %d %v %s %s %v %s", id, limit, active, sort, timeout, ratio, tenant)
+		}
+		if !since.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+			t.Errorf("Unexpected time %v", since)
+		}
+	})
+
+	t.Run("defaults for missing optional parameters", func(t *testing.T) {
+		b := NewBinder(httptest.NewRequest(http.MethodGet, "/", nil))
+		if got := b.Query("limit").Int(20); got != 20 {
+			t.Errorf("Expected default 20, got %d", got)
+		}
+		if got := b.Query("sort").Enum("asc", "asc", "desc"); got != "asc" {
+			t.Errorf("Expected default 'asc', got '%s'", got)
+		}
+		if err := b.Err(); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("collects every failure", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/abc?limit=ten&active=maybe&sort=up", nil)
+		req.SetPathValue("id", "abc")
+
+		b := NewBinder(req)
+		limit := b.Query("limit").Int(20)
+		b.Path("id").Int(0)
+		b.Query("active").Bool(false)
+		b.Query("sort").Enum("asc", "asc", "desc")
+		b.Header("X-Tenant").Required().String("")
+
+		if limit != 20 {
+			t.Errorf("Expected default on parse failure, got %d", limit)
+		}
+
+		err := b.Err()
+		if err == nil {
+			t.Fatal("Expected error")
+		}
+		if err.Code != http.StatusBadRequest {
+			t.Errorf("Expected code 400, got %d", err.Code)
+		}
+
+		expected := []Causes{
+			{Field: "limit", Message: "must be an integer", Location: LocationQuery},
+			{Field: "id", Message: "must be an integer", Location: LocationPath},
+			{Field: "active", Message: "must be a boolean", Location: LocationQuery},
+			{Field: "sort", Message: "must be one of [asc, desc]", Location: LocationQuery},
+			{Field: "X-Tenant", Message: "is required", Location: LocationHeader},
+		}
+		if len(err.Causes) != len(expected) {
+			t.Fatalf("Expected %d causes, got %+v", len(expected), err.Causes)
+		}
+		for i, c := range expected {
+			if err.Causes[i] != c {
+				t.Errorf("Expected cause %+v, got %+v", c, err.Causes[i])
+			}
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package rest_err
+
+import "slices"
+
+// Builder constructs a RestErr fluently:
+//
+//	err := rest_err.Build(http.StatusNotFound).
+//		Message("user not found").
+//		Code("USER_NOT_FOUND").
+//		Cause(dbErr).
+//		Err()
+//
+// Builders are immutable values, every method returns a new Builder,
+// so a partially configured Builder can be shared and extended safely
+type Builder struct {
+	factory *Factory
+	err     RestErr
+}
+
+// Build starts a Builder for the given HTTP status, Err is derived from the status text
+func Build(code int) Builder {
+	return Builder{err: RestErr{Err: errText(code), Code: code}}
+}
+
+// Build starts a Builder whose errors are stamped by the factory's clock
+func (f *Factory) Build(code int) Builder {
+	b := Build(code)
+	b.factory = f
+	return b
+}
+
+// Message sets the message as is, without any formatting
+func (b Builder) Message(message string) Builder {
+	b.err.Message = message
+	return b
+}
+
+// Code sets the application specific error code
+func (b Builder) Code(code string) Builder {
+	b.err.AppCode = code
+	return b
+}
+
+// Cause sets the wrapped underlying error
+func (b Builder) Cause(err error) Builder {
+	b.err.Wrapped = err
+	return b
+}
+
+// Field appends a cause for field
+func (b Builder) Field(field, message string) Builder {
+	return b.Causes(Causes{Field: field, Message: message})
+}
+
+// Causes appends causes
+func (b Builder) Causes(causes ...Causes) Builder {
+	b.err.Causes = append(slices.Clip(b.err.Causes), causes...)
+	return b
+}
+
+// Err returns a new RestErr stamped with the current time, each call returns a distinct value
+func (b Builder) Err() *RestErr {
+	restErr := b.err.Clone()
+	restErr.format = restErr.Message
+	restErr.stack = callers()
+	if b.factory != nil {
+		restErr.Timestamp = b.factory.Now()
+	} else {
+		restErr.Timestamp = now()
+	}
+	return created(restErr)
+}
@@ -0,0 +1,120 @@
+package rest_err
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBuild(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	err := Build(http.StatusNotFound).
+		Message("user 100% not found").
+		Code("USER_NOT_FOUND").
+		Field("id", "unknown id").
+		Cause(dbErr).
+		Err()
+
+	if err.Code != http.StatusNotFound || err.Err != "not found" {
+		t.Errorf("Unexpected status %d %s", err.Code, err.Err)
+	}
+	if err.Message != "user 100% not found" {
+		t.Errorf("Expected literal message, got '%s'", err.Message)
+	}
+	if err.AppCode != "USER_NOT_FOUND" {
+		t.Errorf("Expected app code, got '%s'", err.AppCode)
+	}
+	if len(err.Causes) != 1 || err.Causes[0].Field != "id" {
+		t.Errorf("Unexpected causes %+v", err.Causes)
+	}
+	if !errors.Is(err, dbErr) {
+		t.Error("Expected the cause to be wrapped")
+	}
+	if err.Timestamp.IsZero() {
+		t.Error("Expected timestamp to be set")
+	}
+}
+
+func TestBuilder_Immutable(t *testing.T) {
+	base := Build(http.StatusBadRequest).Message("invalid").Field("a", "bad")
+
+	first := base.Field("b", "bad").Err()
+	second := base.Field("c", "bad").Err()
+	plain := base.Err()
+
+	if len(first.Causes) != 2 || first.Causes[1].Field != "b" {
+		t.Errorf("Unexpected first causes %+v", first.Causes)
+	}
+	if len(second.Causes) != 2 || second.Causes[1].Field != "c" {
+		t.Errorf("Unexpected second causes %+v", second.Causes)
+	}
+	if len(plain.Causes) != 1 {
+		t.Errorf("Expected base builder to be unaffected, got %+v", plain.Causes)
+	}
+
+	plain.Causes[0].Message = "changed"
+	if base.Err().Causes[0].Message != "bad" {
+		t.Error("Expected built errors not to share causes with the builder")
+	}
+}
+
+func TestFactory_Build(t *testing.T) {
+	resetTimestamps(t)
+
+	f := &Factory{Clock: ClockFunc(func() time.Time { return fixedTime })}
+	err := f.Build(http.StatusConflict).Message("taken").Err()
+	if !err.Timestamp.Equal(fixedTime) {
+		t.Errorf("Expected factory clock, got %v", err.Timestamp)
+	}
+}
+
+func TestRestErr_CopyOnWrite(t *testing.T) {
+	sentinel := NewBadRequestValidationError("invalid", []Causes{{Field: "a", Message: "bad"}})
+
+	withCause := sentinel.WithCause(errors.New("boom"))
+	withField := sentinel.WithField("b", "bad")
+	withMessage := sentinel.WithMessage("other")
+	withCode := sentinel.WithAppCode("INVALID")
+	withCauses := sentinel.WithCauses(Causes{Field: "c", Message: "bad"}, Causes{Field: "d", Message: "bad"})
+
+	if sentinel.Wrapped != nil || len(sentinel.Causes) != 1 || sentinel.Message != "invalid" || sentinel.AppCode != "" {
+		t.Errorf("Expected sentinel to be untouched, got %+v", sentinel)
+	}
+	if withCause.Wrapped == nil || len(withField.Causes) != 2 || withMessage.Message != "other" || withCode.AppCode != "INVALID" {
+		t.Error("Expected copies to carry the changes")
+	}
+	if len(withCauses.Causes) != 3 {
+		t.Errorf("Expected 3 causes, got %d", len(withCauses.Causes))
+	}
+}
+
+func TestRestErr_CopyOnWriteConcurrent(t *testing.T) {
+	sentinel := NewNotFoundError("missing")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = sentinel.WithCause(errors.New("boom")).WithField("id", "unknown")
+		}()
+	}
+	wg.Wait()
+
+	if sentinel.Wrapped != nil || len(sentinel.Causes) != 0 {
+		t.Errorf("Expected sentinel to be untouched, got %+v", sentinel)
+	}
+}
+
+func TestRestErr_Clone(t *testing.T) {
+	original := NewConflictValidationError("conflict", []Causes{{Field: "email", Message: "taken"}})
+	clone := original.Clone()
+
+	clone.Causes[0].Message = "changed"
+	clone.Message = "changed"
+	if original.Causes[0].Message != "taken" || original.Message != "conflict" {
+		t.Error("Expected clone to be independent of the original")
+	}
+}
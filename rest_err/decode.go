@@ -0,0 +1,187 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the body size limit applied by DecodeJSON unless overridden
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+type decodeOptions struct {
+	maxBytes              int64
+	disallowUnknownFields bool
+	contentTypes          []string
+}
+
+// DecodeOption configures DecodeJSON
+type DecodeOption func(*decodeOptions)
+
+// WithMaxBodyBytes limits the request body to n bytes, a value <= 0 disables the limit
+func WithMaxBodyBytes(n int64) DecodeOption {
+	return func(o *decodeOptions) {
+		o.maxBytes = n
+	}
+}
+
+// WithDisallowUnknownFields rejects objects containing keys that do not match a destination field
+func WithDisallowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// WithContentTypes replaces the accepted media types.
+// By default application/json and any application/*+json type are accepted
+func WithContentTypes(types ...string) DecodeOption {
+	return func(o *decodeOptions) {
+		o.contentTypes = types
+	}
+}
+
+// DecodeJSON decodes the JSON body of r into v.
+// Every failure is returned as a RestErr ready to be written to the client:
+// 415 for a wrong Content-Type, 413 when the body exceeds the size limit and
+// 400 with causes pointing at the offending field and byte offset otherwise
+func DecodeJSON(r *http.Request, v any, opts ...DecodeOption) *RestErr {
+	o := decodeOptions{maxBytes: DefaultMaxBodyBytes}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if restErr := checkContentType(r.Header.Get("Content-Type"), o.contentTypes); restErr != nil {
+		return restErr
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return NewBadRequestError("request body must not be empty")
+	}
+
+	body := io.Reader(r.Body)
+	if o.maxBytes > 0 {
+		body = http.MaxBytesReader(nil, r.Body, o.maxBytes)
+	}
+
+	dec := json.NewDecoder(body)
+	if o.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return NewBadRequestError("request body must not be empty")
+		}
+		return decodeError(err, dec.InputOffset())
+	}
+
+	offset := dec.InputOffset()
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return decodeError(err, offset)
+		}
+		return NewBadRequestValidationError("invalid JSON body", []Causes{{
+			Message:  fmt.Sprintf("unexpected data after JSON value at byte offset %d", offset),
+			Location: LocationBody,
+		}})
+	}
+
+	return nil
+}
+
+func checkContentType(header string, allowed []string) *RestErr {
+	if header == "" {
+		return NewUnsupportedMediaTypeError("Content-Type header is required")
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return NewUnsupportedMediaTypeError("malformed Content-Type header")
+	}
+
+	if len(allowed) == 0 {
+		if mediaType == "application/json" ||
+			(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json")) {
+			return nil
+		}
+	}
+	for _, t := range allowed {
+		if strings.EqualFold(mediaType, t) {
+			return nil
+		}
+	}
+
+	return NewUnsupportedMediaTypeError("unsupported Content-Type %q", mediaType)
+}
+
+func decodeError(err error, offset int64) *RestErr {
+	var (
+		maxErr    *http.MaxBytesError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.As(err, &maxErr):
+		return NewRequestEntityTooLargeError("request body must not exceed %d bytes", maxErr.Limit).WithCause(err)
+
+	case errors.As(err, &syntaxErr):
+		return NewBadRequestValidationError("invalid JSON body", []Causes{{
+			Message:  fmt.Sprintf("%s at byte offset %d", syntaxErr.Error(), syntaxErr.Offset),
+			Location: LocationBody,
+		}}).WithCause(err)
+
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return NewBadRequestValidationError("invalid JSON body", []Causes{{
+			Message:  fmt.Sprintf("unexpected end of JSON input at byte offset %d", offset),
+			Location: LocationBody,
+		}}).WithCause(err)
+
+	case errors.As(err, &typeErr):
+		return NewBadRequestValidationError("invalid JSON body", []Causes{{
+			Field:    bodyField(typeErr.Field),
+			Message:  fmt.Sprintf("expected %s but got %s at byte offset %d", typeErr.Type, typeErr.Value, typeErr.Offset),
+			Location: LocationBody,
+		}}).WithCause(err)
+
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		if unquoted, uerr := strconv.Unquote(field); uerr == nil {
+			field = unquoted
+		}
+		return NewBadRequestValidationError("invalid JSON body", []Causes{{
+			Field:    field,
+			Message:  fmt.Sprintf("unknown field at byte offset %d", offset),
+			Location: LocationBody,
+		}}).WithCause(err)
+	}
+
+	return NewBadRequestError("invalid JSON body").WithCause(err)
+}
+
+// bodyField converts a field path of encoding/json such as "items.0.sku" to the format of
+// Validator, "items[0].sku", so a request gets a single naming scheme for its causes
+func bodyField(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, part := range strings.Split(path, ".") {
+		if _, err := strconv.Atoi(part); err == nil && i > 0 {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
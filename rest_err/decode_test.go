@@ -0,0 +1,169 @@
+package rest_err
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type decodeTarget struct {
+	Name  string `json:"name"`
+	Age   int    `json:"age"`
+	Inner struct {
+		Count int `json:"count"`
+	} `json:"inner"`
+	Items []struct {
+		SKU string `json:"sku"`
+	} `json:"items"`
+}
+
+func newJSONRequest(body, contentType string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req
+}
+
+func TestDecodeJSON(t *testing.T) {
+	t.Run("valid body", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{"name":"john","age":30}`, "application/json; charset=utf-8"), &v)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if v.Name != "john" || v.Age != 30 {
+			t.Errorf("Expected decoded values, got %+v", v)
+		}
+	})
+
+	t.Run("structured json suffix", func(t *testing.T) {
+		var v decodeTarget
+		if err := DecodeJSON(newJSONRequest(`{}`, "application/merge-patch+json"), &v); err != nil {
+			t.Errorf("Expected +json media type to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("missing content type", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{}`, ""), &v)
+		if err == nil || err.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("Expected 415, got %v", err)
+		}
+	})
+
+	t.Run("wrong content type", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{}`, "text/plain"), &v)
+		if err == nil || err.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("Expected 415, got %v", err)
+		}
+	})
+
+	t.Run("custom content types", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{}`, "text/plain"), &v, WithContentTypes("text/plain"))
+		if err != nil {
+			t.Errorf("Expected custom content type to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(``, "application/json"), &v)
+		if err == nil || err.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %v", err)
+		}
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{"name":"a very long name"}`, "application/json"), &v, WithMaxBodyBytes(8))
+		if err == nil || err.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("Expected 413, got %v", err)
+		}
+		if err.Wrapped == nil {
+			t.Error("Expected the MaxBytesError to be wrapped")
+		}
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{"name": x}`, "application/json"), &v)
+		if err == nil || err.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 400, got %v", err)
+		}
+		if len(err.Causes) != 1 || !strings.Contains(err.Causes[0].Message, "byte offset 10") {
+			t.Errorf("Expected cause with byte offset, got %+v", err.Causes)
+		}
+	})
+
+	t.Run("truncated body", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{"name": "john"`, "application/json"), &v)
+		if err == nil || err.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 400, got %v", err)
+		}
+		if len(err.Causes) != 1 || !strings.Contains(err.Causes[0].Message, "unexpected end") {
+			t.Errorf("Expected unexpected end cause, got %+v", err.Causes)
+		}
+	})
+
+	t.Run("type error", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{"inner":{"count":"three"}}`, "application/json"), &v)
+		if err == nil || err.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 400, got %v", err)
+		}
+		if len(err.Causes) != 1 {
+			t.Fatalf("Expected 1 cause, got %d", len(err.Causes))
+		}
+		if err.Causes[0].Field != "inner.count" || err.Causes[0].Location != LocationBody {
+			t.Errorf("Expected body field 'inner.count', got %+v", err.Causes[0])
+		}
+		if !strings.Contains(err.Causes[0].Message, "expected int but got string") {
+			t.Errorf("Expected type description, got '%s'", err.Causes[0].Message)
+		}
+	})
+
+	t.Run("type error in a list", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{"items":[{"sku":"a"},{"sku":1}]}`, "application/json"), &v)
+		if err == nil || len(err.Causes) != 1 {
+			t.Fatalf("Expected 1 cause, got %v", err)
+		}
+		if err.Causes[0].Field != "items[1].sku" {
+			t.Errorf("Expected field 'items[1].sku', got '%s'", err.Causes[0].Field)
+		}
+	})
+
+	t.Run("unknown field allowed by default", func(t *testing.T) {
+		var v decodeTarget
+		if err := DecodeJSON(newJSONRequest(`{"extra":1}`, "application/json"), &v); err != nil {
+			t.Errorf("Expected unknown fields to be ignored, got %v", err)
+		}
+	})
+
+	t.Run("unknown field disallowed", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{"extra":1}`, "application/json"), &v, WithDisallowUnknownFields())
+		if err == nil || err.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 400, got %v", err)
+		}
+		if len(err.Causes) != 1 || err.Causes[0].Field != "extra" || err.Causes[0].Location != LocationBody {
+			t.Errorf("Expected cause for field 'extra', got %+v", err.Causes)
+		}
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		var v decodeTarget
+		err := DecodeJSON(newJSONRequest(`{"name":"john"} {"name":"jane"}`, "application/json"), &v)
+		if err == nil || err.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 400, got %v", err)
+		}
+		if len(err.Causes) != 1 || !strings.Contains(err.Causes[0].Message, "byte offset 15") {
+			t.Errorf("Expected trailing data cause at offset 15, got %+v", err.Causes)
+		}
+	})
+}
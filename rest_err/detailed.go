@@ -0,0 +1,79 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Detailed is a RestErr carrying a typed detail payload for well-known errors, e.g.
+//
+//	type QuotaExceeded struct {
+//		Limit int `json:"limit"`
+//		Used  int `json:"used"`
+//	}
+//
+//	err := rest_err.NewDetailed(rest_err.NewTooManyRequestsError("quota exceeded"), QuotaExceeded{Limit: 100, Used: 120})
+//
+// The payload is encoded under the "details" member. Detailed unwraps to its RestErr,
+// so ParseError and errors.As keep working and DetailsAs reads the payload on both sides of the wire
+type Detailed[T any] struct {
+	*RestErr
+	Details T // Typed payload, also stored in RestErr.Details
+}
+
+// NewDetailed returns a copy of r carrying details
+func NewDetailed[T any](r *RestErr, details T) *Detailed[T] {
+	clone := r.Clone()
+	clone.Details = details
+	return &Detailed[T]{RestErr: clone, Details: details}
+}
+
+// Unwrap returns the RestErr so errors.As and ParseError can find it
+func (d *Detailed[T]) Unwrap() error {
+	return d.RestErr
+}
+
+// MarshalJSON encodes the RestErr with Details under the "details" member
+func (d *Detailed[T]) MarshalJSON() ([]byte, error) {
+	clone := d.RestErr.Clone()
+	clone.Details = d.Details
+	return json.Marshal(clone)
+}
+
+// UnmarshalJSON decodes a document produced by MarshalJSON, converting the "details" member to a T
+func (d *Detailed[T]) UnmarshalJSON(data []byte) error {
+	var restErr RestErr
+	if err := json.Unmarshal(data, &restErr); err != nil {
+		return err
+	}
+
+	var details T
+	if restErr.Details != nil {
+		raw, err := json.Marshal(restErr.Details)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(raw, &details); err != nil {
+			return err
+		}
+	}
+	restErr.Details = details
+	d.RestErr, d.Details = &restErr, details
+	return nil
+}
+
+// DetailsAs extracts a typed detail payload from err. It matches a Detailed[T] in the chain
+// or, e.g. for errors decoded from a response, converts the RestErr's Details to a T
+func DetailsAs[T any](err error) (T, bool) {
+	var detailed *Detailed[T]
+	if errors.As(err, &detailed) {
+		return detailed.Details, true
+	}
+
+	var zero T
+	restErr, ok := ParseError(err)
+	if !ok || restErr.Details == nil {
+		return zero, false
+	}
+	return convertJSON[T](restErr.Details)
+}
@@ -0,0 +1,124 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type quotaExceeded struct {
+	Limit int `json:"limit"`
+	Used  int `json:"used"`
+}
+
+func TestNewDetailed(t *testing.T) {
+	base := NewTooManyRequestsError("quota exceeded")
+	err := NewDetailed(base, quotaExceeded{Limit: 100, Used: 120})
+
+	if base.Details != nil {
+		t.Error("Expected the base error to be untouched")
+	}
+	if err.Code != http.StatusTooManyRequests || err.Details.Limit != 100 {
+		t.Errorf("Unexpected detailed error %+v", err)
+	}
+
+	t.Run("errors.As and ParseError", func(t *testing.T) {
+		wrapped := fmt.Errorf("handler: %w", err)
+
+		var detailed *Detailed[quotaExceeded]
+		if !errors.As(wrapped, &detailed) || detailed.Details.Used != 120 {
+			t.Error("Expected errors.As to find the Detailed error")
+		}
+
+		restErr, ok := ParseError(wrapped)
+		if !ok || restErr.Code != http.StatusTooManyRequests {
+			t.Fatal("Expected ParseError to find the RestErr")
+		}
+		if _, ok := restErr.Details.(quotaExceeded); !ok {
+			t.Errorf("Expected the RestErr to carry the details, got %T", restErr.Details)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, marshalErr := json.Marshal(err)
+		if marshalErr != nil {
+			t.Fatalf("Unexpected error: %v", marshalErr)
+		}
+		if !strings.Contains(string(data), `"details":{"limit":100,"used":120}`) {
+			t.Errorf("Expected details member, got %s", data)
+		}
+
+		var decoded Detailed[quotaExceeded]
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded.Code != http.StatusTooManyRequests || decoded.Message != "quota exceeded" || decoded.Details != err.Details {
+			t.Errorf("Unexpected decoded error %+v", decoded)
+		}
+		if details, ok := decoded.RestErr.Details.(quotaExceeded); !ok || details != err.Details {
+			t.Errorf("Expected the RestErr to carry the typed details, got %T", decoded.RestErr.Details)
+		}
+	})
+
+	t.Run("json mismatched details", func(t *testing.T) {
+		var decoded Detailed[quotaExceeded]
+		if err := json.Unmarshal([]byte(`{"message":"x","code":429,"details":{"limit":"many"}}`), &decoded); err == nil {
+			t.Error("Expected error for details of another shape")
+		}
+	})
+}
+
+func TestDetailsAs(t *testing.T) {
+	err := NewDetailed(NewTooManyRequestsError("quota exceeded"), quotaExceeded{Limit: 100, Used: 120})
+
+	t.Run("server side", func(t *testing.T) {
+		details, ok := DetailsAs[quotaExceeded](fmt.Errorf("wrapped: %w", err))
+		if !ok || details.Limit != 100 {
+			t.Errorf("Expected details, got %+v (%v)", details, ok)
+		}
+	})
+
+	t.Run("client side", func(t *testing.T) {
+		data, _ := json.Marshal(err)
+		var decoded RestErr
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		details, ok := DetailsAs[quotaExceeded](&decoded)
+		if !ok || details.Used != 120 {
+			t.Errorf("Expected details converted from JSON, got %+v (%v)", details, ok)
+		}
+	})
+
+	t.Run("problem json", func(t *testing.T) {
+		data, _ := json.Marshal(err.ToProblem())
+		var problem Problem
+		if err := json.Unmarshal(data, &problem); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(problem.Meta) != 0 {
+			t.Errorf("Expected details not to be treated as an extension, got %v", problem.Meta)
+		}
+
+		details, ok := DetailsAs[quotaExceeded](problem.ToRestErr())
+		if !ok || details.Limit != 100 {
+			t.Errorf("Expected details from problem+json, got %+v (%v)", details, ok)
+		}
+	})
+
+	t.Run("missing or mismatched", func(t *testing.T) {
+		if _, ok := DetailsAs[quotaExceeded](NewNotFoundError("missing")); ok {
+			t.Error("Expected false without details")
+		}
+		if _, ok := DetailsAs[quotaExceeded](errors.New("plain")); ok {
+			t.Error("Expected false for a plain error")
+		}
+		if _, ok := DetailsAs[[]string](err); ok {
+			t.Error("Expected false for a payload of another shape")
+		}
+	})
+}
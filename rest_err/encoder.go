@@ -0,0 +1,246 @@
+package rest_err
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// KeyStyle controls the casing of JSON member names
+type KeyStyle int
+
+const (
+	// KeySnakeCase uses names such as "error_code", matching the struct tags
+	KeySnakeCase KeyStyle = iota
+	// KeyCamelCase uses names such as "errorCode"
+	KeyCamelCase
+)
+
+// Encoder encodes and decodes RestErr as JSON in a configurable shape, without changing the struct tags.
+// Members are configured by their default names: message, error, code, app_code, causes, details, meta and timestamp.
+// The zero value produces the default document, as returned by json.Marshal
+type Encoder struct {
+	Envelope        string            // Wraps the error in {"<Envelope>": {...}} when set
+	KeyStyle        KeyStyle          // Casing applied to every member name, including causes members
+	Keys            map[string]string // Renames top level members, e.g. {"code": "status"}
+	Omit            []string          // Top level members left out, e.g. "timestamp"
+	TimestampFormat TimestampFormat   // Encoding of the timestamp member, the package format by default
+	MetaPlacement   MetaPlacement     // Where Meta entries are encoded, a nested "meta" object by default
+}
+
+// members lists the default member names in encoding order
+var members = []string{"message", "error", "code", "app_code", "causes", "details", "meta", "timestamp"}
+
+// MarshalJSON encodes the error with a zero Encoder, so the timestamp follows the package format.
+// It has a value receiver so errors held by value encode the same way
+func (r RestErr) MarshalJSON() ([]byte, error) {
+	return (&Encoder{}).Marshal(&r)
+}
+
+// UnmarshalJSON decodes the default document, accepting every TimestampFormat
+func (r *RestErr) UnmarshalJSON(data []byte) error {
+	decoded, err := (&Encoder{}).Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	*r = *decoded
+	return nil
+}
+
+// Marshal encodes r in the configured shape
+func (e *Encoder) Marshal(r *RestErr) ([]byte, error) {
+	var obj jsonObject
+	e.add(&obj, "message", r.Message)
+	e.add(&obj, "error", r.Err)
+	e.add(&obj, "code", r.Code)
+	if r.AppCode != "" {
+		e.add(&obj, "app_code", r.AppCode)
+	}
+	if len(r.Causes) > 0 {
+		e.add(&obj, "causes", e.causes(r.Causes))
+	}
+	if r.Details != nil {
+		e.add(&obj, "details", r.Details)
+	}
+	if len(r.Meta) > 0 && e.MetaPlacement == MetaNested {
+		e.add(&obj, "meta", r.Meta)
+	}
+	if ts := encodeTimestamp(r.Timestamp, e.TimestampFormat); ts != nil {
+		e.add(&obj, "timestamp", ts)
+	}
+	if e.MetaPlacement == MetaTopLevel && !e.omitted("meta") {
+		obj.addMeta(r.Meta, e.keys())
+	}
+	if obj.err != nil {
+		return nil, obj.err
+	}
+
+	if e.Envelope == "" {
+		return obj.bytes(), nil
+	}
+	var envelope jsonObject
+	envelope.add(e.Envelope, json.RawMessage(obj.bytes()))
+	return envelope.bytes(), envelope.err
+}
+
+// Unmarshal decodes a document in the configured shape, unknown members are ignored
+func (e *Encoder) Unmarshal(data []byte) (*RestErr, error) {
+	if e.Envelope != "" {
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return nil, err
+		}
+		inner, ok := envelope[e.Envelope]
+		if !ok {
+			return nil, fmt.Errorf("rest_err: missing %q envelope", e.Envelope)
+		}
+		data = inner
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	r := &RestErr{}
+	var causes []map[string]string
+	var timestamp json.RawMessage
+	for _, m := range []struct {
+		name   string
+		target any
+	}{
+		{"message", &r.Message},
+		{"error", &r.Err},
+		{"code", &r.Code},
+		{"app_code", &r.AppCode},
+		{"causes", &causes},
+		{"details", &r.Details},
+		{"meta", &r.Meta},
+		{"timestamp", &timestamp},
+	} {
+		value, ok := raw[e.key(m.name)]
+		if !ok || e.omitted(m.name) || (m.name == "meta" && e.MetaPlacement != MetaNested) {
+			continue
+		}
+		if err := json.Unmarshal(value, m.target); err != nil {
+			return nil, fmt.Errorf("rest_err: decode %q: %w", e.key(m.name), err)
+		}
+	}
+
+	for _, c := range causes {
+		r.Causes = append(r.Causes, Causes{
+			Field:    c[e.style("field")],
+			Message:  c[e.style("message")],
+			Location: c[e.style("location")],
+		})
+	}
+
+	ts, err := decodeTimestamp(timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("rest_err: decode %q: %w", e.key("timestamp"), err)
+	}
+	r.Timestamp = ts
+
+	if e.MetaPlacement == MetaTopLevel && !e.omitted("meta") {
+		meta, err := decodeMeta(data, e.keys())
+		if err != nil {
+			return nil, err
+		}
+		r.Meta = meta
+	}
+	return r, nil
+}
+
+func (e *Encoder) add(obj *jsonObject, name string, value any) {
+	if e.omitted(name) {
+		return
+	}
+	obj.add(e.key(name), value)
+}
+
+func (e *Encoder) causes(causes []Causes) []json.RawMessage {
+	encoded := make([]json.RawMessage, len(causes))
+	for i, c := range causes {
+		var obj jsonObject
+		obj.add(e.style("field"), c.Field)
+		obj.add(e.style("message"), c.Message)
+		if c.Location != "" {
+			obj.add(e.style("location"), c.Location)
+		}
+		encoded[i] = obj.bytes()
+	}
+	return encoded
+}
+
+func (e *Encoder) omitted(name string) bool {
+	return slices.Contains(e.Omit, name)
+}
+
+// keys returns the resolved names of every member
+func (e *Encoder) keys() []string {
+	keys := make([]string, len(members))
+	for i, name := range members {
+		keys[i] = e.key(name)
+	}
+	return keys
+}
+
+func (e *Encoder) key(name string) string {
+	if renamed, ok := e.Keys[name]; ok {
+		return renamed
+	}
+	return e.style(name)
+}
+
+func (e *Encoder) style(name string) string {
+	if e.KeyStyle == KeyCamelCase {
+		return camelCase(name)
+	}
+	return name
+}
+
+// camelCase converts a snake_case name, e.g. "error_code" to "errorCode"
+func camelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// jsonObject builds a JSON object keeping members in insertion order
+type jsonObject struct {
+	buf bytes.Buffer
+	err error
+}
+
+func (o *jsonObject) add(key string, value any) {
+	if o.err != nil {
+		return
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		o.err = err
+		return
+	}
+	if o.buf.Len() == 0 {
+		o.buf.WriteByte('{')
+	} else {
+		o.buf.WriteByte(',')
+	}
+	k, _ := json.Marshal(key)
+	o.buf.Write(k)
+	o.buf.WriteByte(':')
+	o.buf.Write(encoded)
+}
+
+func (o *jsonObject) bytes() []byte {
+	if o.buf.Len() == 0 {
+		return []byte("{}")
+	}
+	return append(bytes.Clone(o.buf.Bytes()), '}')
+}
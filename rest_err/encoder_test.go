@@ -0,0 +1,183 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func encoderTestErr() *RestErr {
+	restErr := NewBadRequestValidationError("invalid request", []Causes{
+		{Field: "email", Message: "is required"},
+		{Field: "limit", Message: "must be an integer", Location: LocationQuery},
+	})
+	restErr.Timestamp = time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	return restErr
+}
+
+func TestEncoder_Marshal(t *testing.T) {
+	restErr := encoderTestErr()
+
+	t.Run("zero value", func(t *testing.T) {
+		got, err := (&Encoder{}).Marshal(restErr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"message":"invalid request","error":"bad request","code":400,` +
+			`"causes":[{"field":"email","message":"is required"},{"field":"limit","message":"must be an integer","location":"query"}],` +
+			`"timestamp":"2024-01-02T03:04:05.0000006Z"}`
+		if string(got) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+		}
+
+		viaJSON, _ := json.Marshal(restErr)
+		if string(viaJSON) != expected {
+			t.Errorf("Expected json.Marshal to produce the same document, got\n%s", viaJSON)
+		}
+	})
+
+	t.Run("envelope renames and omissions", func(t *testing.T) {
+		enc := &Encoder{
+			Envelope:        "error",
+			Keys:            map[string]string{"code": "status", "error": "reason"},
+			Omit:            []string{"causes"},
+			TimestampFormat: TimestampRFC3339,
+		}
+		got, err := enc.Marshal(restErr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"error":{"message":"invalid request","reason":"bad request","status":400,"timestamp":"2024-01-02T03:04:05Z"}}`
+		if string(got) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+		}
+	})
+
+	t.Run("no causes", func(t *testing.T) {
+		got, err := (&Encoder{Omit: []string{"timestamp"}}).Marshal(NewNotFoundError("missing"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"message":"missing","error":"not found","code":404}`
+		if string(got) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+		}
+	})
+}
+
+func TestEncoder_Unmarshal(t *testing.T) {
+	restErr := encoderTestErr()
+
+	encoders := map[string]*Encoder{
+		"default":  {},
+		"envelope": {Envelope: "error", Keys: map[string]string{"code": "status"}},
+		"camel":    {KeyStyle: KeyCamelCase},
+	}
+
+	for name, enc := range encoders {
+		t.Run(name, func(t *testing.T) {
+			data, err := enc.Marshal(restErr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			decoded, err := enc.Unmarshal(data)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if decoded.Message != restErr.Message || decoded.Err != restErr.Err || decoded.Code != restErr.Code {
+				t.Errorf("Unexpected decoded error %+v", decoded)
+			}
+			if !reflect.DeepEqual(decoded.Causes, restErr.Causes) {
+				t.Errorf("Expected causes %+v, got %+v", restErr.Causes, decoded.Causes)
+			}
+			if !decoded.Timestamp.Equal(restErr.Timestamp) {
+				t.Errorf("Expected timestamp %v, got %v", restErr.Timestamp, decoded.Timestamp)
+			}
+		})
+	}
+
+	t.Run("missing envelope", func(t *testing.T) {
+		if _, err := (&Encoder{Envelope: "error"}).Unmarshal([]byte(`{"message":"x"}`)); err == nil {
+			t.Error("Expected error for missing envelope")
+		}
+	})
+
+	t.Run("wrong member type", func(t *testing.T) {
+		if _, err := (&Encoder{}).Unmarshal([]byte(`{"code":"400"}`)); err == nil {
+			t.Error("Expected error for a string code")
+		}
+	})
+}
+
+func TestCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"message":    "message",
+		"error_code": "errorCode",
+		"a_b_c":      "aBC",
+	}
+	for in, expected := range tests {
+		if got := camelCase(in); got != expected {
+			t.Errorf("camelCase(%q): expected %q, got %q", in, expected, got)
+		}
+	}
+}
+
+func TestWithJSONEncoder(t *testing.T) {
+	enc := &Encoder{Envelope: "error", Keys: map[string]string{"code": "status"}}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	NewWriter(WithJSONEncoder(enc)).Write(rec, req, NewNotFoundError("missing"))
+
+	decoded, err := enc.Unmarshal(rec.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded.Code != http.StatusNotFound || decoded.Message != "missing" {
+		t.Errorf("Unexpected decoded error %+v", decoded)
+	}
+}
+
+func TestEncoder_AppCode(t *testing.T) {
+	restErr := NewNotFoundError("missing").WithAppCode("USER_NOT_FOUND")
+	enc := &Encoder{KeyStyle: KeyCamelCase, Omit: []string{"timestamp"}}
+
+	data, err := enc.Marshal(restErr)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `{"message":"missing","error":"not found","code":404,"appCode":"USER_NOT_FOUND"}`
+	if string(data) != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+	}
+
+	decoded, err := enc.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded.AppCode != "USER_NOT_FOUND" {
+		t.Errorf("Expected app code to round trip, got '%s'", decoded.AppCode)
+	}
+}
+
+func TestRestErr_MarshalJSONByValue(t *testing.T) {
+	restErr := encoderTestErr()
+	expected, err := json.Marshal(restErr)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(struct {
+		Field RestErr   `json:"field"`
+		Slice []RestErr `json:"slice"`
+	}{*restErr, []RestErr{*restErr}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `{"field":` + string(expected) + `,"slice":[` + string(expected) + `]}`
+	if string(data) != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, data)
+	}
+}
@@ -0,0 +1,97 @@
+package rest_err
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth is the number of frames recorded when an error is created
+const maxStackDepth = 32
+
+// callers returns the stack of the caller of the constructor calling it
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pcs)
+	return pcs[:n:n]
+}
+
+// Stack returns the frames of where the error was created, empty for decoded errors
+func (r *RestErr) Stack() []runtime.Frame {
+	if len(r.stack) == 0 {
+		return nil
+	}
+
+	var stack []runtime.Frame
+	frames := runtime.CallersFrames(r.stack)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, frame)
+		if !more {
+			return stack
+		}
+	}
+}
+
+// Fingerprint returns a stable identifier grouping occurrences of the same error, e.g. "9f86d081884c7d65".
+// It is derived from the status, AppCode, message key, template or printf format, the function that
+// created the error and the types of the wrapped errors. Interpolated values, causes, timestamps, file
+// paths and line numbers are ignored, so "user 1 not found" and "user 2 not found" created from the
+// same format in the same function share a fingerprint. Errors created by helpers of this package,
+// such as Validate or the 500 of a plain error passed to Write, use the function calling the helper
+func (r *RestErr) Fingerprint() string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d\x00%s\x00%s\x00%s", r.Code, r.AppCode, cmp.Or(r.Key, r.Template, r.format), r.site())
+	for _, t := range wrappedTypes(r.Wrapped) {
+		fmt.Fprintf(h, "\x00%s", t)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// site returns the first function of the stack outside this package, test files excepted,
+// empty for decoded errors
+func (r *RestErr) site() string {
+	if len(r.stack) == 0 {
+		return ""
+	}
+
+	frames := runtime.CallersFrames(r.stack)
+	first, more := frames.Next()
+	for frame := first; ; frame, more = frames.Next() {
+		if !inPackage(frame) {
+			return frame.Function
+		}
+		if !more {
+			return first.Function
+		}
+	}
+}
+
+// packagePrefix is the prefix of the functions of this package, "github.com/BrunoPolaski/go-rest-err/rest_err."
+var packagePrefix = strings.TrimSuffix(runtime.FuncForPC(reflect.ValueOf(callers).Pointer()).Name(), "callers")
+
+// inPackage reports whether frame is a function of this package outside test files
+func inPackage(frame runtime.Frame) bool {
+	return strings.HasPrefix(frame.Function, packagePrefix) && !strings.HasSuffix(frame.File, "_test.go")
+}
+
+// wrappedTypes returns the types of the errors in the chain of err, depth first
+func wrappedTypes(err error) []string {
+	var types []string
+	for err != nil {
+		types = append(types, fmt.Sprintf("%T", err))
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				types = append(types, wrappedTypes(e)...)
+			}
+			break
+		}
+		err = errors.Unwrap(err)
+	}
+	return types
+}
@@ -0,0 +1,134 @@
+package rest_err
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func findUser(id int) *RestErr {
+	return NewNotFoundError("user %d not found", id)
+}
+
+func findOrder(id int) *RestErr {
+	return NewNotFoundError("order %d not found", id)
+}
+
+func TestRestErr_Fingerprint(t *testing.T) {
+	t.Run("independent of interpolated values and timestamps", func(t *testing.T) {
+		first := findUser(1)
+		time.Sleep(time.Millisecond)
+		second := findUser(2)
+
+		if first.Fingerprint() != second.Fingerprint() {
+			t.Errorf("Expected equal fingerprints, got %s and %s", first.Fingerprint(), second.Fingerprint())
+		}
+		if len(first.Fingerprint()) != 16 {
+			t.Errorf("Expected 16 hex characters, got %s", first.Fingerprint())
+		}
+	})
+
+	t.Run("creation site", func(t *testing.T) {
+		if findUser(1).Fingerprint() == findOrder(1).Fingerprint() {
+			t.Error("Expected errors created in different functions to differ")
+		}
+
+		user := NewNotFoundError("user %d not found", 1)
+		order := NewNotFoundError("order %d not found", 1)
+		if user.Fingerprint() == order.Fingerprint() {
+			t.Error("Expected errors with different formats in the same function to differ")
+		}
+
+		same := func() [2]*RestErr {
+			first := NewNotFoundError("user %d not found", 1)
+			// Lines between the two errors must not matter
+			second := NewNotFoundError("user %d not found", 2)
+			return [2]*RestErr{first, second}
+		}()
+		if same[0].Fingerprint() != same[1].Fingerprint() {
+			t.Error("Expected fingerprints to ignore line numbers")
+		}
+	})
+
+	t.Run("package helpers use their caller", func(t *testing.T) {
+		type login struct {
+			Email string `json:"email" validate:"required"`
+		}
+		type signup struct {
+			Name string `json:"name" validate:"required"`
+		}
+		validator := NewValidator()
+		if validator.Validate(login{}).Fingerprint() == validator.Validate(signup{}).Fingerprint() {
+			t.Error("Expected validation errors of different types to differ")
+		}
+
+		recent := NewRecentErrors(10)
+		hook := AddHook(recent)
+		serve := func(h http.HandlerFunc) {
+			h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		}
+		serve(func(w http.ResponseWriter, r *http.Request) { WriteError(w, r, errors.New("db down")) })
+		serve(func(w http.ResponseWriter, r *http.Request) { WriteError(w, r, errors.New("cache down")) })
+		hook.Close()
+		if entries := recent.Entries(); len(entries) != 2 {
+			t.Errorf("Expected plain errors written by different handlers to differ, got %+v", entries)
+		}
+	})
+
+	t.Run("template", func(t *testing.T) {
+		newErr := func(template string, id int) *RestErr {
+			return NewTemplateError(http.StatusNotFound, template, Params{"id": id})
+		}
+		if newErr("user {id} not found", 1).Fingerprint() != newErr("user {id} not found", 2).Fingerprint() {
+			t.Error("Expected the same template to share a fingerprint")
+		}
+		if newErr("user {id} not found", 1).Fingerprint() == newErr("order {id} not found", 1).Fingerprint() {
+			t.Error("Expected different templates to differ")
+		}
+	})
+
+	t.Run("status, app code and wrapped types", func(t *testing.T) {
+		base := findUser(1)
+		variants := []*RestErr{
+			base.WithAppCode("USER_NOT_FOUND"),
+			base.WithCause(context.DeadlineExceeded),
+			base.WithCause(fmt.Errorf("lookup: %w", fs.ErrNotExist)),
+			{Code: http.StatusGone, stack: base.stack},
+		}
+		for i, variant := range variants {
+			if variant.Fingerprint() == base.Fingerprint() {
+				t.Errorf("Expected variant %d to differ from the base fingerprint", i)
+			}
+		}
+
+		if base.WithCause(errors.New("a")).Fingerprint() != base.WithCause(errors.New("b")).Fingerprint() {
+			t.Error("Expected wrapped errors of the same type to share a fingerprint")
+		}
+	})
+
+	t.Run("builder and decoded errors", func(t *testing.T) {
+		build := func(id int) *RestErr {
+			return Build(http.StatusConflict).Template("email {id} taken", Params{"id": id}).Err()
+		}
+		if build(1).Fingerprint() != build(2).Fingerprint() {
+			t.Error("Expected built errors from the same template to share a fingerprint")
+		}
+		if Build(http.StatusConflict).Message("email taken").Err().Fingerprint() == Build(http.StatusConflict).Message("name taken").Err().Fingerprint() {
+			t.Error("Expected built errors with different messages to differ")
+		}
+
+		data, _ := json.Marshal(findUser(1))
+		var first, second RestErr
+		_ = json.Unmarshal(data, &first)
+		_ = json.Unmarshal(data, &second)
+		if first.Fingerprint() != second.Fingerprint() {
+			t.Error("Expected decoded errors to share a fingerprint")
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type URLs of the google.rpc error details supported by GoogleEncoder
+const (
+	GoogleTypeErrorInfo  = "type.googleapis.com/google.rpc.ErrorInfo"
+	GoogleTypeBadRequest = "type.googleapis.com/google.rpc.BadRequest"
+	GoogleTypeRetryInfo  = "type.googleapis.com/google.rpc.RetryInfo"
+)
+
+// GoogleStatus is the JSON representation of google.rpc.Status used by AIP-193 error responses
+type GoogleStatus struct {
+	Code    int               `json:"code" example:"400"`                // HTTP status code
+	Message string            `json:"message" example:"invalid request"` // Developer facing message
+	Status  string            `json:"status" example:"INVALID_ARGUMENT"` // Canonical error code name
+	Details []json.RawMessage `json:"details,omitempty"`                 // Detail messages, each with an "@type" member
+}
+
+// GoogleErrorInfo is google.rpc.ErrorInfo
+type GoogleErrorInfo struct {
+	Type     string            `json:"@type"`
+	Reason   string            `json:"reason" example:"USER_NOT_FOUND"`
+	Domain   string            `json:"domain,omitempty" example:"users.example.com"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// GoogleBadRequest is google.rpc.BadRequest
+type GoogleBadRequest struct {
+	Type            string                 `json:"@type"`
+	FieldViolations []GoogleFieldViolation `json:"fieldViolations"`
+}
+
+// GoogleFieldViolation is google.rpc.BadRequest.FieldViolation
+type GoogleFieldViolation struct {
+	Field       string `json:"field" example:"email"`
+	Description string `json:"description" example:"invalid email address"`
+}
+
+// GoogleRetryInfo is google.rpc.RetryInfo. Attach it with NewDetailed to emit a RetryInfo detail
+type GoogleRetryInfo struct {
+	RetryDelay time.Duration
+}
+
+type googleRetryInfo struct {
+	Type       string `json:"@type"`
+	RetryDelay string `json:"retryDelay"`
+}
+
+// GoogleEncoder encodes and decodes RestErr as {"error": google.rpc.Status} following AIP-193,
+// without depending on protobuf libraries:
+//
+//   - AppCode and Meta become an ErrorInfo detail (Meta values are formatted as strings)
+//   - Causes become a BadRequest detail with one field violation each
+//   - a GoogleRetryInfo in Details becomes a RetryInfo detail
+type GoogleEncoder struct {
+	Domain string // ErrorInfo domain, e.g. "users.example.com"
+}
+
+// Marshal encodes r as an AIP-193 error response
+func (g *GoogleEncoder) Marshal(r *RestErr) ([]byte, error) {
+	return json.Marshal(map[string]GoogleStatus{"error": g.ToStatus(r)})
+}
+
+// ToStatus converts r to a google.rpc.Status
+func (g *GoogleEncoder) ToStatus(r *RestErr) GoogleStatus {
+	status := GoogleStatus{Code: r.Code, Message: r.Message, Status: r.ToGRPCCode().String()}
+
+	if r.AppCode != "" || len(r.Meta) > 0 {
+		info := GoogleErrorInfo{Type: GoogleTypeErrorInfo, Reason: r.AppCode, Domain: g.Domain}
+		for _, key := range slices.Sorted(maps.Keys(r.Meta)) {
+			if info.Metadata == nil {
+				info.Metadata = map[string]string{}
+			}
+			info.Metadata[key] = fmt.Sprint(r.Meta[key])
+		}
+		status.Details = appendGoogleDetail(status.Details, info)
+	}
+
+	if len(r.Causes) > 0 {
+		badRequest := GoogleBadRequest{Type: GoogleTypeBadRequest}
+		for _, c := range r.Causes {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, GoogleFieldViolation{Field: c.Field, Description: c.Message})
+		}
+		status.Details = appendGoogleDetail(status.Details, badRequest)
+	}
+
+	if retry, ok := r.Details.(GoogleRetryInfo); ok {
+		status.Details = appendGoogleDetail(status.Details, googleRetryInfo{
+			Type:       GoogleTypeRetryInfo,
+			RetryDelay: strconv.FormatFloat(retry.RetryDelay.Seconds(), 'f', -1, 64) + "s",
+		})
+	}
+	return status
+}
+
+func appendGoogleDetail(details []json.RawMessage, detail any) []json.RawMessage {
+	data, _ := json.Marshal(detail)
+	return append(details, data)
+}
+
+// Unmarshal decodes an AIP-193 error response. Unknown detail types are ignored
+func (g *GoogleEncoder) Unmarshal(data []byte) (*RestErr, error) {
+	var envelope struct {
+		Error *GoogleStatus `json:"error"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Error == nil {
+		return nil, fmt.Errorf("rest_err: missing \"error\" member")
+	}
+	return g.FromStatus(*envelope.Error)
+}
+
+// FromStatus converts a google.rpc.Status to a RestErr
+func (g *GoogleEncoder) FromStatus(status GoogleStatus) (*RestErr, error) {
+	code := status.Code
+	if code == 0 {
+		grpcCode, _ := ParseGRPCCode(status.Status)
+		code = grpcCode.HTTPStatus()
+	}
+	r := &RestErr{Message: status.Message, Err: errText(code), Code: code}
+
+	for _, raw := range status.Details {
+		var typed struct {
+			Type string `json:"@type"`
+		}
+		if err := json.Unmarshal(raw, &typed); err != nil {
+			return nil, fmt.Errorf("rest_err: decode detail: %w", err)
+		}
+
+		switch typed.Type {
+		case GoogleTypeErrorInfo:
+			var info GoogleErrorInfo
+			if err := json.Unmarshal(raw, &info); err != nil {
+				return nil, fmt.Errorf("rest_err: decode ErrorInfo: %w", err)
+			}
+			r.AppCode = info.Reason
+			for key, value := range info.Metadata {
+				if r.Meta == nil {
+					r.Meta = Meta{}
+				}
+				r.Meta[key] = value
+			}
+
+		case GoogleTypeBadRequest:
+			var badRequest GoogleBadRequest
+			if err := json.Unmarshal(raw, &badRequest); err != nil {
+				return nil, fmt.Errorf("rest_err: decode BadRequest: %w", err)
+			}
+			for _, v := range badRequest.FieldViolations {
+				r.Causes = append(r.Causes, Causes{Field: v.Field, Message: v.Description})
+			}
+
+		case GoogleTypeRetryInfo:
+			var retry googleRetryInfo
+			if err := json.Unmarshal(raw, &retry); err != nil {
+				return nil, fmt.Errorf("rest_err: decode RetryInfo: %w", err)
+			}
+			seconds, err := strconv.ParseFloat(strings.TrimSuffix(retry.RetryDelay, "s"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("rest_err: decode RetryInfo: invalid retryDelay %q", retry.RetryDelay)
+			}
+			r.Details = GoogleRetryInfo{RetryDelay: time.Duration(seconds * float64(time.Second))}
+		}
+	}
+	return r, nil
+}
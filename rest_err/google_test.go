@@ -0,0 +1,99 @@
+package rest_err
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoogleEncoder_Marshal(t *testing.T) {
+	enc := &GoogleEncoder{Domain: "users.example.com"}
+
+	t.Run("minimal", func(t *testing.T) {
+		data, err := enc.Marshal(NewNotFoundError("user not found"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"error":{"code":404,"message":"user not found","status":"NOT_FOUND"}}`
+		if string(data) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+		}
+	})
+
+	t.Run("details", func(t *testing.T) {
+		restErr := NewBadRequestValidationError("invalid user", []Causes{{Field: "email", Message: "invalid email address"}}).
+			WithAppCode("INVALID_USER").
+			WithMeta("attempt", 3)
+
+		data, err := enc.Marshal(restErr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"error":{"code":400,"message":"invalid user","status":"INVALID_ARGUMENT","details":[` +
+			`{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"INVALID_USER","domain":"users.example.com","metadata":{"attempt":"3"}},` +
+			`{"@type":"type.googleapis.com/google.rpc.BadRequest","fieldViolations":[{"field":"email","description":"invalid email address"}]}]}}`
+		if string(data) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+		}
+	})
+
+	t.Run("retry info", func(t *testing.T) {
+		restErr := NewDetailed(NewTooManyRequestsError("slow down"), GoogleRetryInfo{RetryDelay: 1500 * time.Millisecond})
+		data, err := enc.Marshal(restErr.RestErr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(string(data), `{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"1.5s"}`) {
+			t.Errorf("Expected RetryInfo detail, got %s", data)
+		}
+	})
+}
+
+func TestGoogleEncoder_Unmarshal(t *testing.T) {
+	enc := &GoogleEncoder{}
+
+	t.Run("round trip", func(t *testing.T) {
+		original := NewDetailed(
+			NewBadRequestValidationError("invalid user", []Causes{{Field: "email", Message: "invalid email address"}}).WithAppCode("INVALID_USER"),
+			GoogleRetryInfo{RetryDelay: 30 * time.Second},
+		)
+		data, _ := enc.Marshal(original.RestErr)
+
+		decoded, err := enc.Unmarshal(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded.Code != http.StatusBadRequest || decoded.Err != "bad request" || decoded.Message != "invalid user" {
+			t.Errorf("Unexpected error %+v", decoded)
+		}
+		if decoded.AppCode != "INVALID_USER" {
+			t.Errorf("Expected app code from ErrorInfo, got '%s'", decoded.AppCode)
+		}
+		if len(decoded.Causes) != 1 || decoded.Causes[0].Field != "email" {
+			t.Errorf("Expected causes from field violations, got %+v", decoded.Causes)
+		}
+		if retry, ok := decoded.Details.(GoogleRetryInfo); !ok || retry.RetryDelay != 30*time.Second {
+			t.Errorf("Expected RetryInfo details, got %#v", decoded.Details)
+		}
+	})
+
+	t.Run("status without code and unknown details", func(t *testing.T) {
+		data := `{"error":{"message":"taken","status":"ALREADY_EXISTS","details":[{"@type":"type.googleapis.com/google.rpc.Help","links":[]}]}}`
+		decoded, err := enc.Unmarshal([]byte(data))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded.Code != http.StatusConflict {
+			t.Errorf("Expected 409, got %d", decoded.Code)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, data := range []string{`{}`, `not json`, `{"error":{"code":429,"details":[{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"soon"}]}}`} {
+			if _, err := enc.Unmarshal([]byte(data)); err == nil {
+				t.Errorf("Expected error for %s", data)
+			}
+		}
+	})
+}
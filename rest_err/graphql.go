@@ -0,0 +1,133 @@
+package rest_err
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// GraphQLError is an entry of the "errors" list of a GraphQL response
+type GraphQLError struct {
+	Message    string            `json:"message" example:"user not found"`
+	Locations  []GraphQLLocation `json:"locations,omitempty"`  // Locations in the query document
+	Path       []any             `json:"path,omitempty"`       // Response path of the field, field names and list indexes
+	Extensions map[string]any    `json:"extensions,omitempty"` // code, status, causes, details and Meta entries
+}
+
+// GraphQLLocation is a location in a GraphQL query document
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Extension members written by ToGraphQL, Meta entries with these names are left out
+var graphQLExtensions = []string{"code", "status", "causes", "details"}
+
+// graphQLCodes maps common GraphQL server error codes to HTTP statuses
+var graphQLCodes = map[string]int{
+	"BAD_USER_INPUT":            http.StatusBadRequest,
+	"GRAPHQL_PARSE_FAILED":      http.StatusBadRequest,
+	"GRAPHQL_VALIDATION_FAILED": http.StatusBadRequest,
+	"UNAUTHENTICATED":           http.StatusUnauthorized,
+	"FORBIDDEN":                 http.StatusForbidden,
+	"INTERNAL_SERVER_ERROR":     http.StatusInternalServerError,
+}
+
+func (e *GraphQLError) Error() string {
+	return e.Message
+}
+
+// ToGraphQL converts the error to a GraphQL error for the field at path.
+// extensions.code is the AppCode, or the status text in upper snake case (e.g. "NOT_FOUND")
+func (r *RestErr) ToGraphQL(path []any, locations ...GraphQLLocation) GraphQLError {
+	extensions := map[string]any{
+		"code":   r.graphQLCode(),
+		"status": r.Code,
+	}
+	if len(r.Causes) > 0 {
+		extensions["causes"] = r.Causes
+	}
+	if r.Details != nil {
+		extensions["details"] = r.Details
+	}
+	for key, value := range r.Meta {
+		if !slices.Contains(graphQLExtensions, key) {
+			extensions[key] = value
+		}
+	}
+
+	return GraphQLError{
+		Message:    r.Message,
+		Locations:  locations,
+		Path:       path,
+		Extensions: extensions,
+	}
+}
+
+// FormatGraphQLError converts any error to a GraphQL error, see NewRestErrFromError.
+// It returns false for a nil err, so resolvers can pass their error through unconditionally
+func FormatGraphQLError(err error, path []any, locations ...GraphQLLocation) (GraphQLError, bool) {
+	restErr := NewRestErrFromError(err)
+	if restErr == nil {
+		return GraphQLError{}, false
+	}
+	return restErr.ToGraphQL(path, locations...), true
+}
+
+// FromGraphQL converts a GraphQL error back to a RestErr. The status is read from
+// extensions.status, or derived from well known extensions.code values, defaulting to 500
+func FromGraphQL(e GraphQLError) *RestErr {
+	code, _ := e.Extensions["code"].(string)
+
+	status, ok := convertJSON[int](e.Extensions["status"])
+	if !ok || status == 0 {
+		status, ok = graphQLCodes[code]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+	}
+
+	r := &RestErr{
+		Message: e.Message,
+		Err:     errText(status),
+		Code:    status,
+		Details: e.Extensions["details"],
+	}
+	if code != "" && code != graphQLCodeFor(r.Err) {
+		r.AppCode = code
+	}
+	if causes, ok := convertJSON[[]Causes](e.Extensions["causes"]); ok {
+		r.Causes = causes
+	}
+	for key, value := range e.Extensions {
+		if slices.Contains(graphQLExtensions, key) {
+			continue
+		}
+		if r.Meta == nil {
+			r.Meta = Meta{}
+		}
+		r.Meta[key] = value
+	}
+	return r
+}
+
+func (r *RestErr) graphQLCode() string {
+	if r.AppCode != "" {
+		return r.AppCode
+	}
+	if r.Err != "" {
+		return graphQLCodeFor(r.Err)
+	}
+	return graphQLCodeFor(errText(r.Code))
+}
+
+// graphQLCodeFor returns text in upper snake case, e.g. "not found" becomes "NOT_FOUND"
+func graphQLCodeFor(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, text)
+}
@@ -0,0 +1,115 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRestErr_ToGraphQL(t *testing.T) {
+	t.Run("status text code", func(t *testing.T) {
+		restErr := NewNotFoundError("user not found")
+		gqlErr := restErr.ToGraphQL([]any{"user", 0, "profile"}, GraphQLLocation{Line: 2, Column: 3})
+
+		data, err := json.Marshal(gqlErr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"message":"user not found","locations":[{"line":2,"column":3}],"path":["user",0,"profile"],"extensions":{"code":"NOT_FOUND","status":404}}`
+		if string(data) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+		}
+	})
+
+	t.Run("app code, causes and meta", func(t *testing.T) {
+		restErr := NewBadRequestValidationError("invalid input", []Causes{{Field: "email", Message: "invalid email address"}}).
+			WithAppCode("INVALID_USER").
+			WithMeta("attempt", 3).
+			WithMeta("status", "ignored")
+
+		gqlErr := restErr.ToGraphQL(nil)
+		if gqlErr.Extensions["code"] != "INVALID_USER" || gqlErr.Extensions["status"] != http.StatusBadRequest {
+			t.Errorf("Unexpected extensions %v", gqlErr.Extensions)
+		}
+		if causes, ok := gqlErr.Extensions["causes"].([]Causes); !ok || len(causes) != 1 {
+			t.Errorf("Expected causes extension, got %v", gqlErr.Extensions["causes"])
+		}
+		if gqlErr.Extensions["attempt"] != 3 {
+			t.Errorf("Expected meta extension, got %v", gqlErr.Extensions)
+		}
+	})
+
+	t.Run("multi word status text", func(t *testing.T) {
+		gqlErr := NewRequestEntityTooLargeError("too big").ToGraphQL(nil)
+		if gqlErr.Extensions["code"] != "REQUEST_ENTITY_TOO_LARGE" {
+			t.Errorf("Expected REQUEST_ENTITY_TOO_LARGE, got %v", gqlErr.Extensions["code"])
+		}
+	})
+}
+
+func TestFormatGraphQLError(t *testing.T) {
+	gqlErr, ok := FormatGraphQLError(errors.New("boom"), []any{"users"})
+	if !ok {
+		t.Fatal("Expected a GraphQL error")
+	}
+	if gqlErr.Extensions["status"] != http.StatusInternalServerError || gqlErr.Extensions["code"] != "INTERNAL_SERVER_ERROR" {
+		t.Errorf("Unexpected extensions %v", gqlErr.Extensions)
+	}
+	if gqlErr.Message == "boom" {
+		t.Error("Expected plain errors not to leak their text")
+	}
+
+	if _, ok := FormatGraphQLError(nil, []any{"users"}); ok {
+		t.Error("Expected no GraphQL error for a nil error")
+	}
+}
+
+func TestFromGraphQL(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		original := NewConflictValidationError("email taken", []Causes{{Field: "email", Message: "taken"}}).
+			WithAppCode("EMAIL_TAKEN").
+			WithMeta("attempt", 3)
+
+		data, _ := json.Marshal(original.ToGraphQL([]any{"createUser"}))
+		var gqlErr GraphQLError
+		if err := json.Unmarshal(data, &gqlErr); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		restErr := FromGraphQL(gqlErr)
+		if restErr.Code != http.StatusConflict || restErr.Err != "conflict" || restErr.Message != "email taken" {
+			t.Errorf("Unexpected error %+v", restErr)
+		}
+		if restErr.AppCode != "EMAIL_TAKEN" {
+			t.Errorf("Expected app code, got '%s'", restErr.AppCode)
+		}
+		if len(restErr.Causes) != 1 || restErr.Causes[0].Field != "email" {
+			t.Errorf("Unexpected causes %+v", restErr.Causes)
+		}
+		if attempt, ok := MetaValue[int](restErr, "attempt"); !ok || attempt != 3 {
+			t.Errorf("Expected meta, got %v", restErr.Meta)
+		}
+	})
+
+	t.Run("status text code is not an app code", func(t *testing.T) {
+		restErr := FromGraphQL(NewNotFoundError("missing").ToGraphQL(nil))
+		if restErr.Code != http.StatusNotFound || restErr.AppCode != "" {
+			t.Errorf("Unexpected error %+v", restErr)
+		}
+	})
+
+	t.Run("well known codes without status", func(t *testing.T) {
+		tests := map[string]int{
+			"UNAUTHENTICATED": http.StatusUnauthorized,
+			"BAD_USER_INPUT":  http.StatusBadRequest,
+			"SOMETHING_ELSE":  http.StatusInternalServerError,
+		}
+		for code, expected := range tests {
+			restErr := FromGraphQL(GraphQLError{Message: "failed", Extensions: map[string]any{"code": code}})
+			if restErr.Code != expected {
+				t.Errorf("Expected %d for %s, got %d", expected, code, restErr.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package rest_err
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// GRPCCode is a canonical gRPC status code, numbered as in google.rpc.Code
+type GRPCCode uint32
+
+const (
+	GRPCOK                 GRPCCode = 0
+	GRPCCanceled           GRPCCode = 1
+	GRPCUnknown            GRPCCode = 2
+	GRPCInvalidArgument    GRPCCode = 3
+	GRPCDeadlineExceeded   GRPCCode = 4
+	GRPCNotFound           GRPCCode = 5
+	GRPCAlreadyExists      GRPCCode = 6
+	GRPCPermissionDenied   GRPCCode = 7
+	GRPCResourceExhausted  GRPCCode = 8
+	GRPCFailedPrecondition GRPCCode = 9
+	GRPCAborted            GRPCCode = 10
+	GRPCOutOfRange         GRPCCode = 11
+	GRPCUnimplemented      GRPCCode = 12
+	GRPCInternal           GRPCCode = 13
+	GRPCUnavailable        GRPCCode = 14
+	GRPCDataLoss           GRPCCode = 15
+	GRPCUnauthenticated    GRPCCode = 16
+)
+
+// StatusClientClosedRequest is the non standard HTTP status used for cancelled requests
+const StatusClientClosedRequest = 499
+
+var grpcCodeNames = [...]string{
+	GRPCOK:                 "OK",
+	GRPCCanceled:           "CANCELLED",
+	GRPCUnknown:            "UNKNOWN",
+	GRPCInvalidArgument:    "INVALID_ARGUMENT",
+	GRPCDeadlineExceeded:   "DEADLINE_EXCEEDED",
+	GRPCNotFound:           "NOT_FOUND",
+	GRPCAlreadyExists:      "ALREADY_EXISTS",
+	GRPCPermissionDenied:   "PERMISSION_DENIED",
+	GRPCResourceExhausted:  "RESOURCE_EXHAUSTED",
+	GRPCFailedPrecondition: "FAILED_PRECONDITION",
+	GRPCAborted:            "ABORTED",
+	GRPCOutOfRange:         "OUT_OF_RANGE",
+	GRPCUnimplemented:      "UNIMPLEMENTED",
+	GRPCInternal:           "INTERNAL",
+	GRPCUnavailable:        "UNAVAILABLE",
+	GRPCDataLoss:           "DATA_LOSS",
+	GRPCUnauthenticated:    "UNAUTHENTICATED",
+}
+
+// String returns the canonical name of the code, e.g. "NOT_FOUND"
+func (c GRPCCode) String() string {
+	if int(c) < len(grpcCodeNames) {
+		return grpcCodeNames[c]
+	}
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// ParseGRPCCode returns the code with the canonical name, e.g. "NOT_FOUND"
+func ParseGRPCCode(name string) (GRPCCode, bool) {
+	for code, codeName := range grpcCodeNames {
+		if codeName == name {
+			return GRPCCode(code), true
+		}
+	}
+	return GRPCUnknown, false
+}
+
+// HTTPStatus returns the HTTP status for the code, following google.rpc.Code
+func (c GRPCCode) HTTPStatus() int {
+	switch c {
+	case GRPCOK:
+		return http.StatusOK
+	case GRPCCanceled:
+		return StatusClientClosedRequest
+	case GRPCInvalidArgument, GRPCFailedPrecondition, GRPCOutOfRange:
+		return http.StatusBadRequest
+	case GRPCDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case GRPCNotFound:
+		return http.StatusNotFound
+	case GRPCAlreadyExists, GRPCAborted:
+		return http.StatusConflict
+	case GRPCPermissionDenied:
+		return http.StatusForbidden
+	case GRPCResourceExhausted:
+		return http.StatusTooManyRequests
+	case GRPCUnimplemented:
+		return http.StatusNotImplemented
+	case GRPCUnavailable:
+		return http.StatusServiceUnavailable
+	case GRPCUnauthenticated:
+		return http.StatusUnauthorized
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCodeFromHTTP returns the gRPC code for an HTTP status, following the HTTP to gRPC
+// mapping used by Google's API gateways. Statuses without an exact match fall back by class
+func GRPCCodeFromHTTP(status int) GRPCCode {
+	switch status {
+	case http.StatusBadRequest:
+		return GRPCInvalidArgument
+	case http.StatusUnauthorized:
+		return GRPCUnauthenticated
+	case http.StatusForbidden:
+		return GRPCPermissionDenied
+	case http.StatusNotFound:
+		return GRPCNotFound
+	case http.StatusConflict:
+		return GRPCAborted
+	case http.StatusRequestedRangeNotSatisfiable:
+		return GRPCOutOfRange
+	case http.StatusTooManyRequests:
+		return GRPCResourceExhausted
+	case StatusClientClosedRequest:
+		return GRPCCanceled
+	case http.StatusNotImplemented:
+		return GRPCUnimplemented
+	case http.StatusServiceUnavailable:
+		return GRPCUnavailable
+	case http.StatusGatewayTimeout:
+		return GRPCDeadlineExceeded
+	}
+	switch {
+	case status >= 200 && status < 300:
+		return GRPCOK
+	case status >= 400 && status < 500:
+		return GRPCFailedPrecondition
+	case status >= 500 && status < 600:
+		return GRPCInternal
+	}
+	return GRPCUnknown
+}
+
+// ToGRPCCode returns the gRPC code for the error's HTTP status
+func (r *RestErr) ToGRPCCode() GRPCCode {
+	return GRPCCodeFromHTTP(r.Code)
+}
+
+// FromGRPCCode creates a RestErr with the HTTP status of a gRPC code, the message is used as is
+func FromGRPCCode(code GRPCCode, message string) *RestErr {
+	status := code.HTTPStatus()
+	return created(&RestErr{
+		Message:   message,
+		format:    message,
+		Err:       errText(status),
+		Code:      status,
+		Timestamp: now(),
+		stack:     callers(),
+	})
+}
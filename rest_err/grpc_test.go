@@ -0,0 +1,100 @@
+package rest_err
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRestErr_ToGRPCCode(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected GRPCCode
+	}{
+		{http.StatusOK, GRPCOK},
+		{http.StatusNoContent, GRPCOK},
+		{http.StatusBadRequest, GRPCInvalidArgument},
+		{http.StatusUnauthorized, GRPCUnauthenticated},
+		{http.StatusForbidden, GRPCPermissionDenied},
+		{http.StatusNotFound, GRPCNotFound},
+		{http.StatusConflict, GRPCAborted},
+		{http.StatusRequestedRangeNotSatisfiable, GRPCOutOfRange},
+		{http.StatusTooManyRequests, GRPCResourceExhausted},
+		{StatusClientClosedRequest, GRPCCanceled},
+		{http.StatusUnprocessableEntity, GRPCFailedPrecondition},
+		{http.StatusInternalServerError, GRPCInternal},
+		{http.StatusNotImplemented, GRPCUnimplemented},
+		{http.StatusBadGateway, GRPCInternal},
+		{http.StatusServiceUnavailable, GRPCUnavailable},
+		{http.StatusGatewayTimeout, GRPCDeadlineExceeded},
+		{http.StatusFound, GRPCUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			err := &RestErr{Code: tt.status}
+			if code := err.ToGRPCCode(); code != tt.expected {
+				t.Errorf("Expected %s for %d, got %s", tt.expected, tt.status, code)
+			}
+		})
+	}
+}
+
+func TestFromGRPCCode(t *testing.T) {
+	tests := []struct {
+		code     GRPCCode
+		expected int
+	}{
+		{GRPCOK, http.StatusOK},
+		{GRPCCanceled, StatusClientClosedRequest},
+		{GRPCUnknown, http.StatusInternalServerError},
+		{GRPCInvalidArgument, http.StatusBadRequest},
+		{GRPCDeadlineExceeded, http.StatusGatewayTimeout},
+		{GRPCNotFound, http.StatusNotFound},
+		{GRPCAlreadyExists, http.StatusConflict},
+		{GRPCPermissionDenied, http.StatusForbidden},
+		{GRPCResourceExhausted, http.StatusTooManyRequests},
+		{GRPCFailedPrecondition, http.StatusBadRequest},
+		{GRPCAborted, http.StatusConflict},
+		{GRPCOutOfRange, http.StatusBadRequest},
+		{GRPCUnimplemented, http.StatusNotImplemented},
+		{GRPCInternal, http.StatusInternalServerError},
+		{GRPCUnavailable, http.StatusServiceUnavailable},
+		{GRPCDataLoss, http.StatusInternalServerError},
+		{GRPCUnauthenticated, http.StatusUnauthorized},
+		{GRPCCode(42), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			err := FromGRPCCode(tt.code, "upstream 100% failed")
+			if err.Code != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, err.Code)
+			}
+			if err.Message != "upstream 100% failed" {
+				t.Errorf("Expected literal message, got '%s'", err.Message)
+			}
+			if err.Err == "" {
+				t.Error("Expected error text to be set")
+			}
+		})
+	}
+}
+
+func TestGRPCCode_String(t *testing.T) {
+	if GRPCCanceled.String() != "CANCELLED" || GRPCAlreadyExists.String() != "ALREADY_EXISTS" {
+		t.Errorf("Unexpected names %s %s", GRPCCanceled, GRPCAlreadyExists)
+	}
+	if GRPCCode(42).String() != "Code(42)" {
+		t.Errorf("Expected Code(42), got %s", GRPCCode(42))
+	}
+
+	for code := GRPCOK; code <= GRPCUnauthenticated; code++ {
+		parsed, ok := ParseGRPCCode(code.String())
+		if !ok || parsed != code {
+			t.Errorf("Expected %s to round trip, got %s", code, parsed)
+		}
+	}
+	if _, ok := ParseGRPCCode("not_found"); ok {
+		t.Error("Expected names to be case sensitive")
+	}
+}
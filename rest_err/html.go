@@ -0,0 +1,162 @@
+package rest_err
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// HTMLRenderer renders RestErr as HTML error pages through html/template, so Message and
+// Causes are always escaped. Templates receive the RestErr fields plus Title, the status text
+type HTMLRenderer struct {
+	fallback *template.Template
+	pages    map[string]*template.Template
+}
+
+// htmlPage is the data passed to error page templates
+type htmlPage struct {
+	*RestErr
+	Title string
+}
+
+var htmlFuncs = template.FuncMap{
+	"statusText": http.StatusText,
+}
+
+var defaultHTMLTemplate = template.Must(template.New("error").Funcs(htmlFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Code}} {{.Title}}</title>
+</head>
+<body>
+<h1>{{.Code}} {{.Title}}</h1>
+<p>{{.Message}}</p>
+{{- if .Causes}}
+<ul>
+{{- range .Causes}}
+<li>{{if .Field}}<strong>{{.Field}}</strong>: {{end}}{{.Message}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+// NewHTMLRenderer returns a renderer using only the built-in error page
+func NewHTMLRenderer() *HTMLRenderer {
+	return &HTMLRenderer{fallback: defaultHTMLTemplate, pages: map[string]*template.Template{}}
+}
+
+// LoadHTMLTemplates parses the files matching patterns in fsys (typically an embed.FS) as error pages.
+// Pages are chosen by file name without extension: "404.html" for a single status, "4xx.html" for a
+// status class and "error.html" replacing the built-in page. Other files hold layouts and partials
+// used through {{template}}; each page is parsed in its own copy of them, so pages can define the
+// same blocks
+func LoadHTMLTemplates(fsys fs.FS, patterns ...string) (*HTMLRenderer, error) {
+	var pages, shared []string
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rest_err: parse HTML templates: %w", err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("rest_err: parse HTML templates: pattern matches no files: %#q", pattern)
+		}
+		for _, file := range files {
+			if seen[file] {
+				continue
+			}
+			seen[file] = true
+			if isHTMLPage(pageName(file)) {
+				pages = append(pages, file)
+			} else {
+				shared = append(shared, file)
+			}
+		}
+	}
+
+	base := template.New("").Funcs(htmlFuncs)
+	for _, file := range shared {
+		if err := parseHTMLFile(base, fsys, file); err != nil {
+			return nil, err
+		}
+	}
+
+	h := NewHTMLRenderer()
+	for _, file := range pages {
+		set, err := base.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("rest_err: parse HTML templates: %w", err)
+		}
+		if err := parseHTMLFile(set, fsys, file); err != nil {
+			return nil, err
+		}
+		t := set.Lookup(path.Base(file))
+		if name := pageName(file); name == "error" {
+			h.fallback = t
+		} else {
+			h.pages[name] = t
+		}
+	}
+	return h, nil
+}
+
+// parseHTMLFile parses file into set as a template named after its base name, like ParseFS
+func parseHTMLFile(set *template.Template, fsys fs.FS, file string) error {
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return fmt.Errorf("rest_err: parse HTML templates: %w", err)
+	}
+	if _, err := set.New(path.Base(file)).Parse(string(data)); err != nil {
+		return fmt.Errorf("rest_err: parse HTML templates: %w", err)
+	}
+	return nil
+}
+
+// pageName returns the file name without directory and extension
+func pageName(file string) string {
+	name := path.Base(file)
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// isHTMLPage reports whether name selects an error page: "error", a status such as "404" or a class such as "4xx"
+func isHTMLPage(name string) bool {
+	if name == "error" {
+		return true
+	}
+	if len(name) != 3 || name[0] < '1' || name[0] > '5' {
+		return false
+	}
+	if name[1:] == "xx" {
+		return true
+	}
+	_, err := strconv.Atoi(name)
+	return err == nil
+}
+
+// Render writes the error page for restErr to w
+func (h *HTMLRenderer) Render(w io.Writer, restErr *RestErr) error {
+	title := http.StatusText(restErr.Code)
+	if title == "" {
+		title = restErr.Err
+	}
+	return h.page(restErr.Code).Execute(w, htmlPage{RestErr: restErr, Title: title})
+}
+
+func (h *HTMLRenderer) page(code int) *template.Template {
+	status := strconv.Itoa(code)
+	if t, ok := h.pages[status]; ok {
+		return t
+	}
+	if t, ok := h.pages[status[:1]+"xx"]; ok {
+		return t
+	}
+	return h.fallback
+}
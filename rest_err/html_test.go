@@ -0,0 +1,111 @@
+package rest_err
+
+import (
+	"embed"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+//go:embed testdata/html
+var testHTMLTemplates embed.FS
+
+func renderHTML(t *testing.T, h *HTMLRenderer, restErr *RestErr) string {
+	t.Helper()
+	var b strings.Builder
+	if err := h.Render(&b, restErr); err != nil {
+		t.Fatalf("Unexpected render error: %v", err)
+	}
+	return b.String()
+}
+
+func TestHTMLRenderer_Default(t *testing.T) {
+	restErr := NewBadRequestValidationError(`<script>alert("x")</script>`, []Causes{{Field: "name<b>", Message: "is <i>required</i>"}})
+	body := renderHTML(t, NewHTMLRenderer(), restErr)
+
+	if !strings.Contains(body, "<title>400 Bad Request</title>") {
+		t.Errorf("Expected title with status text, got %s", body)
+	}
+	if strings.Contains(body, "<script>") || strings.Contains(body, "<b>") || strings.Contains(body, "<i>") {
+		t.Errorf("Expected message and causes to be escaped, got %s", body)
+	}
+	if !strings.Contains(body, "&lt;script&gt;") || !strings.Contains(body, "is &lt;i&gt;required&lt;/i&gt;") {
+		t.Errorf("Expected escaped content, got %s", body)
+	}
+}
+
+func TestLoadHTMLTemplates(t *testing.T) {
+	h, err := LoadHTMLTemplates(testHTMLTemplates, "testdata/html/*")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		err      *RestErr
+		expected string
+	}{
+		{"status page with layout", NewNotFoundError("user <42>"), `<main><p class="missing">Nothing here: user &lt;42&gt;</p></main>`},
+		{"class page", NewBadGatewayError("upstream"), `<p class="server">Something broke (502)</p>`},
+		{"fallback override", NewConflictValidationError("taken", []Causes{{Field: "email", Message: "duplicate"}}), `<p class="generic">Conflict: taken [email duplicate]</p>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := renderHTML(t, h, tt.err)
+			if strings.TrimSpace(body) != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, strings.TrimSpace(body))
+			}
+		})
+	}
+}
+
+func TestLoadHTMLTemplates_SharedBlocks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.tmpl": {Data: []byte(`{{define "layout"}}<main>{{template "content" .}}</main>{{end}}`)},
+		"404.html":    {Data: []byte(`{{define "content"}}missing: {{.Message}}{{end}}{{template "layout" .}}`)},
+		"500.html":    {Data: []byte(`{{define "content"}}broken: {{.Message}}{{end}}{{template "layout" .}}`)},
+	}
+	h, err := LoadHTMLTemplates(fsys, "*")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if body := renderHTML(t, h, NewNotFoundError("user")); body != "<main>missing: user</main>" {
+		t.Errorf("Expected the 404 page content, got %q", body)
+	}
+	if body := renderHTML(t, h, NewInternalServerError("db")); body != "<main>broken: db</main>" {
+		t.Errorf("Expected the 500 page content, got %q", body)
+	}
+}
+
+func TestLoadHTMLTemplates_Errors(t *testing.T) {
+	fsys := fstest.MapFS{"broken.html": {Data: []byte("{{.Message")}}
+	if _, err := LoadHTMLTemplates(fsys, "*.html"); err == nil {
+		t.Error("Expected parse error")
+	}
+	if _, err := LoadHTMLTemplates(fsys, "missing/*.html"); err == nil {
+		t.Error("Expected error for a pattern matching no files")
+	}
+}
+
+func TestWithHTMLRenderer(t *testing.T) {
+	h, err := LoadHTMLTemplates(testHTMLTemplates, "testdata/html/*")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/users/42", nil)
+	req.Header.Set("Accept", "text/html")
+	rec := httptest.NewRecorder()
+	NewWriter(WithHTMLRenderer(h)).Write(rec, req, NewNotFoundError("user not found"))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `class="missing"`) {
+		t.Errorf("Expected custom 404 page, got %s", rec.Body.String())
+	}
+}
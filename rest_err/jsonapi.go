@@ -0,0 +1,226 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultJSONAPIPointerPrefix is the JSON pointer body causes are resolved against
+const DefaultJSONAPIPointerPrefix = "/data/attributes"
+
+// JSONAPIDocument is a JSON:API error document
+type JSONAPIDocument struct {
+	Errors []JSONAPIError `json:"errors"`
+	Meta   Meta           `json:"meta,omitempty"` // Holds "message" when the error has causes
+}
+
+// JSONAPIError is a JSON:API error object
+type JSONAPIError struct {
+	ID     string         `json:"id,omitempty"`
+	Status string         `json:"status,omitempty" example:"400"`           // HTTP status code, as a string
+	Code   string         `json:"code,omitempty" example:"USER_NOT_FOUND"`  // Application specific error code
+	Title  string         `json:"title,omitempty" example:"Bad Request"`    // Short summary, the status text
+	Detail string         `json:"detail,omitempty" example:"invalid email"` // Message of the error or cause
+	Source *JSONAPISource `json:"source,omitempty"`                         // What caused the error
+	Meta   Meta           `json:"meta,omitempty"`
+}
+
+// JSONAPISource points to the part of the request that caused an error
+type JSONAPISource struct {
+	Pointer   string `json:"pointer,omitempty" example:"/data/attributes/email"` // JSON pointer into the request body
+	Parameter string `json:"parameter,omitempty" example:"page"`                 // URI parameter
+	Header    string `json:"header,omitempty" example:"If-Match"`                // Request header
+}
+
+// JSONAPIEncoder encodes and decodes RestErr as JSON:API error documents.
+// An error without causes becomes a single error object, otherwise each cause becomes
+// its own error object and the message is kept in the document meta.
+// JSON:API has no source for path parameters, their error objects have no source and
+// carry the field in their meta instead, e.g. {"location":"path","field":"id"}
+type JSONAPIEncoder struct {
+	PointerPrefix string // Prefix of body cause pointers, defaults to DefaultJSONAPIPointerPrefix
+}
+
+// Marshal encodes r as a JSON:API error document
+func (e *JSONAPIEncoder) Marshal(r *RestErr) ([]byte, error) {
+	return json.Marshal(e.ToDocument(r))
+}
+
+// ToDocument converts r to a JSON:API error document
+func (e *JSONAPIEncoder) ToDocument(r *RestErr) JSONAPIDocument {
+	title := http.StatusText(r.Code)
+	if title == "" {
+		title = r.Err
+	}
+	base := JSONAPIError{
+		Status: strconv.Itoa(r.Code),
+		Code:   r.AppCode,
+		Title:  title,
+		Detail: r.Message,
+		Meta:   r.Meta,
+	}
+
+	if len(r.Causes) == 0 {
+		return JSONAPIDocument{Errors: []JSONAPIError{base}}
+	}
+
+	doc := JSONAPIDocument{Meta: Meta{"message": r.Message}}
+	for _, c := range r.Causes {
+		obj := base
+		obj.Detail = c.Message
+		obj.Source = e.source(c)
+		if c.Location == LocationPath {
+			obj.Meta = maps.Clone(r.Meta)
+			if obj.Meta == nil {
+				obj.Meta = Meta{}
+			}
+			obj.Meta["location"], obj.Meta["field"] = LocationPath, c.Field
+		}
+		doc.Errors = append(doc.Errors, obj)
+	}
+	return doc
+}
+
+// Unmarshal decodes a JSON:API error document
+func (e *JSONAPIEncoder) Unmarshal(data []byte) (*RestErr, error) {
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return e.FromDocument(doc)
+}
+
+// FromDocument converts a JSON:API error document to a RestErr. Error objects with a
+// source become causes. Mixed statuses are combined like Aggregate does
+func (e *JSONAPIEncoder) FromDocument(doc JSONAPIDocument) (*RestErr, error) {
+	if len(doc.Errors) == 0 {
+		return nil, fmt.Errorf("rest_err: JSON:API document has no errors")
+	}
+
+	codes := make([]int, 0, len(doc.Errors))
+	for _, obj := range doc.Errors {
+		code, err := strconv.Atoi(obj.Status)
+		if err != nil {
+			code = http.StatusInternalServerError
+		}
+		codes = append(codes, code)
+	}
+
+	first := doc.Errors[0]
+	code := aggregateCode(codes)
+	r := &RestErr{
+		Message: first.Detail,
+		Err:     errText(code),
+		Code:    code,
+		AppCode: first.Code,
+		Meta:    first.Meta,
+	}
+	if _, ok := pathParameter(first); ok {
+		r.Meta = maps.Clone(first.Meta)
+		delete(r.Meta, "location")
+		delete(r.Meta, "field")
+		if len(r.Meta) == 0 {
+			r.Meta = nil
+		}
+	}
+	if message, ok := doc.Meta["message"].(string); ok {
+		r.Message = message
+	}
+
+	for _, obj := range doc.Errors {
+		if obj.Source != nil {
+			r.Causes = append(r.Causes, e.cause(obj))
+		} else if field, ok := pathParameter(obj); ok {
+			r.Causes = append(r.Causes, Causes{Field: field, Message: obj.Detail, Location: LocationPath})
+		}
+	}
+	return r, nil
+}
+
+func (e *JSONAPIEncoder) pointerPrefix() string {
+	if e.PointerPrefix == "" {
+		return DefaultJSONAPIPointerPrefix
+	}
+	return e.PointerPrefix
+}
+
+// source returns the source of a cause. Query parameters are reported as parameters,
+// headers as headers, path parameters have no source and everything else is a pointer into the body
+func (e *JSONAPIEncoder) source(c Causes) *JSONAPISource {
+	switch c.Location {
+	case LocationPath:
+		return nil
+	case LocationQuery:
+		return &JSONAPISource{Parameter: c.Field}
+	case LocationHeader:
+		return &JSONAPISource{Header: c.Field}
+	}
+	return &JSONAPISource{Pointer: e.pointerPrefix() + fieldPointer(c.Field)}
+}
+
+// cause converts an error object with a source to a cause
+func (e *JSONAPIEncoder) cause(obj JSONAPIError) Causes {
+	switch {
+	case obj.Source.Parameter != "":
+		return Causes{Field: obj.Source.Parameter, Message: obj.Detail, Location: LocationQuery}
+	case obj.Source.Header != "":
+		return Causes{Field: obj.Source.Header, Message: obj.Detail, Location: LocationHeader}
+	}
+	pointer := strings.TrimPrefix(obj.Source.Pointer, e.pointerPrefix())
+	return Causes{Field: pointerField(pointer), Message: obj.Detail, Location: LocationBody}
+}
+
+// pathParameter returns the field of an error object for a path parameter cause
+func pathParameter(obj JSONAPIError) (string, bool) {
+	field, ok := obj.Meta["field"].(string)
+	return field, ok && obj.Meta["location"] == LocationPath
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// fieldPointer converts a cause field such as "items[0].sku" to a JSON pointer, "/items/0/sku"
+func fieldPointer(field string) string {
+	if field == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, part := range strings.Split(field, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		b.WriteString("/" + pointerEscaper.Replace(name))
+		for rest != "" {
+			var index string
+			index, rest, _ = strings.Cut(rest, "]")
+			b.WriteString("/" + pointerEscaper.Replace(index))
+			rest = strings.TrimPrefix(rest, "[")
+		}
+	}
+	return b.String()
+}
+
+// pointerField converts a JSON pointer such as "/items/0/sku" to a cause field, "items[0].sku".
+// Map keys cannot be told apart from object members and come back as "labels.key"
+func pointerField(pointer string) string {
+	if pointer == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = pointerUnescaper.Replace(token)
+		if _, err := strconv.Atoi(token); err == nil && i > 0 {
+			b.WriteString("[" + token + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(token)
+	}
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package rest_err
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestJSONAPIEncoder_Marshal(t *testing.T) {
+	enc := &JSONAPIEncoder{}
+
+	t.Run("without causes", func(t *testing.T) {
+		data, err := enc.Marshal(NewNotFoundError("user not found").WithAppCode("USER_NOT_FOUND"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"errors":[{"status":"404","code":"USER_NOT_FOUND","title":"Not Found","detail":"user not found"}]}`
+		if string(data) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+		}
+	})
+
+	t.Run("one error object per cause", func(t *testing.T) {
+		restErr := NewBadRequestValidationError("invalid request", []Causes{
+			{Field: "items[0].sku", Message: "is required"},
+			{Field: "page", Message: "must be a number", Location: LocationQuery},
+			{Field: "If-Match", Message: "is required", Location: LocationHeader},
+		})
+
+		data, err := enc.Marshal(restErr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"errors":[` +
+			`{"status":"400","title":"Bad Request","detail":"is required","source":{"pointer":"/data/attributes/items/0/sku"}},` +
+			`{"status":"400","title":"Bad Request","detail":"must be a number","source":{"parameter":"page"}},` +
+			`{"status":"400","title":"Bad Request","detail":"is required","source":{"header":"If-Match"}}],` +
+			`"meta":{"message":"invalid request"}}`
+		if string(data) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+		}
+	})
+
+	t.Run("pointer prefix", func(t *testing.T) {
+		enc := &JSONAPIEncoder{PointerPrefix: "/data"}
+		doc := enc.ToDocument(NewBadRequestValidationError("invalid", []Causes{{Field: "a/b.c~d", Message: "bad"}}))
+		if pointer := doc.Errors[0].Source.Pointer; pointer != "/data/a~1b/c~0d" {
+			t.Errorf("Expected escaped pointer, got %s", pointer)
+		}
+	})
+}
+
+func TestJSONAPIEncoder_Unmarshal(t *testing.T) {
+	enc := &JSONAPIEncoder{}
+
+	t.Run("round trip", func(t *testing.T) {
+		original := NewUnprocessableEntityError("invalid order", []Causes{
+			{Field: "items[0].sku", Message: "is required", Location: LocationBody},
+			{Field: "address.city", Message: "unknown", Location: LocationBody},
+			{Field: "X-Tenant", Message: "is required", Location: LocationHeader},
+		}).WithAppCode("INVALID_ORDER")
+		data, _ := enc.Marshal(original)
+
+		decoded, err := enc.Unmarshal(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded.Code != http.StatusUnprocessableEntity || decoded.Message != "invalid order" || decoded.AppCode != "INVALID_ORDER" {
+			t.Errorf("Unexpected error %+v", decoded)
+		}
+		if len(decoded.Causes) != 3 {
+			t.Fatalf("Expected 3 causes, got %+v", decoded.Causes)
+		}
+		for i, c := range decoded.Causes {
+			if c != original.Causes[i] {
+				t.Errorf("Expected %+v, got %+v", original.Causes[i], c)
+			}
+		}
+	})
+
+	t.Run("path parameters", func(t *testing.T) {
+		original := NewBadRequestValidationError("invalid id", []Causes{
+			{Field: "id", Message: "must be a number", Location: LocationPath},
+			{Field: "page", Message: "must be a number", Location: LocationQuery},
+		}).WithMeta("attempt", 3)
+		data, _ := enc.Marshal(original)
+		expected := `{"errors":[` +
+			`{"status":"400","title":"Bad Request","detail":"must be a number","meta":{"attempt":3,"field":"id","location":"path"}},` +
+			`{"status":"400","title":"Bad Request","detail":"must be a number","source":{"parameter":"page"},"meta":{"attempt":3}}],` +
+			`"meta":{"message":"invalid id"}}`
+		if string(data) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+		}
+
+		decoded, err := enc.Unmarshal(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(decoded.Causes) != 2 || decoded.Causes[0] != original.Causes[0] || decoded.Causes[1] != original.Causes[1] {
+			t.Errorf("Expected %+v, got %+v", original.Causes, decoded.Causes)
+		}
+		if len(decoded.Meta) != 1 || decoded.Meta["attempt"] != float64(3) {
+			t.Errorf("Expected the path cause meta to be left out, got %v", decoded.Meta)
+		}
+	})
+
+	t.Run("single error object", func(t *testing.T) {
+		decoded, err := enc.Unmarshal([]byte(`{"errors":[{"status":"409","detail":"email taken","meta":{"attempt":3}}]}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded.Code != http.StatusConflict || decoded.Message != "email taken" || len(decoded.Causes) != 0 {
+			t.Errorf("Unexpected error %+v", decoded)
+		}
+		if attempt, ok := MetaValue[int](decoded, "attempt"); !ok || attempt != 3 {
+			t.Errorf("Expected meta, got %v", decoded.Meta)
+		}
+	})
+
+	t.Run("mixed statuses", func(t *testing.T) {
+		decoded, err := enc.Unmarshal([]byte(`{"errors":[{"status":"404"},{"status":"409"}]}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", decoded.Code)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, data := range []string{`{"errors":[]}`, `not json`} {
+			if _, err := enc.Unmarshal([]byte(data)); err == nil {
+				t.Errorf("Expected error for %s", data)
+			}
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package rest_err
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Params holds named message parameters, referenced as {name} in message templates
+type Params map[string]any
+
+// WithKey returns a copy of the error with a translation key and its parameters.
+// Message is kept as the text used when no translation is found
+func (r *RestErr) WithKey(key string, params Params) *RestErr {
+	clone := r.Clone()
+	clone.Key = key
+	clone.Params = maps.Clone(params)
+	return clone
+}
+
+// Key sets the translation key of the message and its parameters
+func (b Builder) Key(key string, params Params) Builder {
+	b.err.Key = key
+	b.err.Params = maps.Clone(params)
+	return b
+}
+
+// Bundle holds translated message templates per language tag, e.g.
+//
+//	{"user.not_found": "usuário {id} não encontrado", "validation.required": "o campo {field} é obrigatório"}
+//
+// Templates reference the error's Params by name. Cause templates can also use {field}.
+// A Bundle is safe for concurrent use
+type Bundle struct {
+	mu       sync.RWMutex
+	fallback string
+	tags     map[string]string            // Language tags as added, by lowercase tag
+	messages map[string]map[string]string // Templates by lowercase tag and key
+}
+
+// NewBundle returns an empty bundle. Fallback is the language used when none of the
+// requested languages is available, e.g. "en"
+func NewBundle(fallback string) *Bundle {
+	return &Bundle{
+		fallback: strings.ToLower(fallback),
+		tags:     map[string]string{},
+		messages: map[string]map[string]string{},
+	}
+}
+
+// AddMessages adds message templates for a language tag such as "pt-BR"
+func (b *Bundle) AddMessages(lang string, messages map[string]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tag := strings.ToLower(lang)
+	if b.messages[tag] == nil {
+		b.tags[tag] = lang
+		b.messages[tag] = map[string]string{}
+	}
+	maps.Copy(b.messages[tag], messages)
+}
+
+// LoadJSON adds the message templates of a JSON object for a language tag.
+// Nested objects are flattened into dotted keys, {"user": {"not_found": "..."}} defines "user.not_found"
+func (b *Bundle) LoadJSON(lang string, data []byte) error {
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return fmt.Errorf("rest_err: load %s messages: %w", lang, err)
+	}
+
+	messages := map[string]string{}
+	if err := flattenMessages(messages, "", tree); err != nil {
+		return fmt.Errorf("rest_err: load %s messages: %w", lang, err)
+	}
+	b.AddMessages(lang, messages)
+	return nil
+}
+
+func flattenMessages(messages map[string]string, prefix string, tree map[string]any) error {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value := value.(type) {
+		case string:
+			messages[key] = value
+		case map[string]any:
+			if err := flattenMessages(messages, key, value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q is not a string", key)
+		}
+	}
+	return nil
+}
+
+// LoadFS loads the JSON files matching the patterns, e.g. "locales/*.json". Each file
+// is named after its language tag, "locales/pt-BR.json" holds the "pt-BR" messages
+func (b *Bundle) LoadFS(fsys fs.FS, patterns ...string) error {
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return err
+			}
+			lang := strings.TrimSuffix(path.Base(file), path.Ext(file))
+			if err := b.LoadJSON(lang, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Languages returns the language tags of the bundle, sorted
+func (b *Bundle) Languages() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return slices.Sorted(maps.Values(b.tags))
+}
+
+// Match returns the fallback chain of bundle languages for an Accept-Language header.
+// Requested languages are tried by quality, each followed by its less specific forms
+// ("pt-BR" then "pt") and, when none of those exist, by regional variants ("pt" then "pt-BR").
+// The bundle's fallback language ends the chain
+func (b *Bundle) Match(acceptLanguage string) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	requested := parseQualityList(acceptLanguage)
+	slices.SortStableFunc(requested, func(a, b qualityValue) int {
+		return cmp.Compare(b.q, a.q)
+	})
+
+	var chain []string
+	add := func(tag string) bool {
+		if _, ok := b.messages[tag]; !ok {
+			return false
+		}
+		if !slices.Contains(chain, b.tags[tag]) {
+			chain = append(chain, b.tags[tag])
+		}
+		return true
+	}
+
+	for _, lang := range requested {
+		if lang.q <= 0 || lang.value == "*" {
+			continue
+		}
+
+		found := false
+		for tag := lang.value; tag != ""; {
+			found = add(tag) || found
+			i := strings.LastIndex(tag, "-")
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+		if found {
+			continue
+		}
+
+		base, _, _ := strings.Cut(lang.value, "-")
+		for _, tag := range slices.Sorted(maps.Keys(b.messages)) {
+			if strings.HasPrefix(tag, base+"-") {
+				add(tag)
+			}
+		}
+	}
+	add(b.fallback)
+	return chain
+}
+
+// Translate formats the template of key in the first language of langs defining it,
+// returning the message and that language
+func (b *Bundle) Translate(langs []string, key string, params Params) (string, string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, lang := range langs {
+		if template, ok := b.messages[strings.ToLower(lang)][key]; ok {
+			return interpolate(template, params), lang, true
+		}
+	}
+	return "", "", false
+}
+
+// Localize returns a copy of r with the messages of r and its causes translated along
+// the langs fallback chain, see Match. The message template is used as key when r has no Key.
+// Messages without a key or translation are kept.
+// The returned language is the one the message was translated to, or empty when nothing was translated
+func (b *Bundle) Localize(r *RestErr, langs []string) (*RestErr, string) {
+	localized := r.Clone()
+
+	var language string
+	if key := cmp.Or(r.Key, r.Template); key != "" {
+		if message, lang, ok := b.Translate(langs, key, r.Params); ok {
+			localized.Message, language = message, lang
+		}
+	}
+
+	for i, c := range localized.Causes {
+		if c.Key == "" {
+			continue
+		}
+		params := maps.Clone(r.Params)
+		if params == nil {
+			params = Params{}
+		}
+		params["field"] = c.Field
+
+		if message, lang, ok := b.Translate(langs, c.Key, params); ok {
+			localized.Causes[i].Message = message
+			language = cmp.Or(language, lang)
+		}
+	}
+	return localized, language
+}
@@ -0,0 +1,157 @@
+package rest_err
+
+import (
+	"embed"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/locales
+var testLocales embed.FS
+
+func newTestBundle(t *testing.T) *Bundle {
+	t.Helper()
+
+	b := NewBundle("en")
+	if err := b.LoadFS(testLocales, "testdata/locales/*.json"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return b
+}
+
+func TestBundle_Load(t *testing.T) {
+	b := newTestBundle(t)
+
+	if languages := b.Languages(); !slices.Equal(languages, []string{"en", "es", "pt-BR"}) {
+		t.Errorf("Unexpected languages %v", languages)
+	}
+
+	message, lang, ok := b.Translate([]string{"pt-BR"}, "user.not_found", Params{"id": 42})
+	if !ok || lang != "pt-BR" || message != "usuário 42 não encontrado" {
+		t.Errorf("Unexpected translation '%s' (%s)", message, lang)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		if err := b.LoadJSON("fr", []byte(`{"user": {"not_found": 1}}`)); err == nil {
+			t.Error("Expected error for a non string message")
+		}
+		if err := b.LoadJSON("fr", []byte(`not json`)); err == nil {
+			t.Error("Expected error for invalid JSON")
+		}
+	})
+}
+
+func TestBundle_Match(t *testing.T) {
+	b := newTestBundle(t)
+	b.AddMessages("pt-PT", map[string]string{"user.not_found": "utilizador {id} não encontrado"})
+
+	tests := []struct {
+		header   string
+		expected []string
+	}{
+		{"", []string{"en"}},
+		{"pt-BR", []string{"pt-BR", "en"}},
+		{"PT-br", []string{"pt-BR", "en"}},
+		{"pt", []string{"pt-BR", "pt-PT", "en"}},
+		{"pt-AO", []string{"pt-BR", "pt-PT", "en"}},
+		{"es;q=0.5, pt-PT", []string{"pt-PT", "es", "en"}},
+		{"fr, *", []string{"en"}},
+		{"es, en;q=0", []string{"es", "en"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if chain := b.Match(tt.header); !slices.Equal(chain, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, chain)
+			}
+		})
+	}
+}
+
+func TestBundle_Localize(t *testing.T) {
+	b := newTestBundle(t)
+	original := NewBadRequestValidationError("user 42 not found", []Causes{
+		{Field: "email", Message: "email is required", Key: "validation.required"},
+		{Field: "name", Message: "name is too long"},
+	}).WithKey("user.not_found", Params{"id": 42})
+
+	localized, lang := b.Localize(original, []string{"pt-BR", "en"})
+	if lang != "pt-BR" || localized.Message != "usuário 42 não encontrado" {
+		t.Errorf("Unexpected message '%s' (%s)", localized.Message, lang)
+	}
+	if localized.Causes[0].Message != "o campo email é obrigatório" || localized.Causes[1].Message != "name is too long" {
+		t.Errorf("Unexpected causes %+v", localized.Causes)
+	}
+	if original.Message != "user 42 not found" || original.Causes[0].Message != "email is required" {
+		t.Error("Expected the original error to be untouched")
+	}
+
+	t.Run("fallback chain per key", func(t *testing.T) {
+		localized, lang := b.Localize(original, []string{"es", "en"})
+		if lang != "es" || localized.Message != "usuario 42 no encontrado" || localized.Causes[0].Message != "email is required" {
+			t.Errorf("Unexpected localization '%s' (%s) %+v", localized.Message, lang, localized.Causes)
+		}
+	})
+
+	t.Run("without keys", func(t *testing.T) {
+		localized, lang := b.Localize(NewNotFoundError("missing"), []string{"pt-BR"})
+		if lang != "" || localized.Message != "missing" {
+			t.Errorf("Unexpected localization '%s' (%s)", localized.Message, lang)
+		}
+	})
+}
+
+func TestWriter_Localization(t *testing.T) {
+	wr := NewWriter(WithBundle(newTestBundle(t)))
+	restErr := NewNotFoundError("user 42 not found").WithKey("user.not_found", Params{"id": 42})
+
+	t.Run("negotiated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		req.Header.Set("Accept-Language", "fr;q=0.9, pt;q=0.8")
+		rec := httptest.NewRecorder()
+		wr.Write(rec, req, restErr)
+
+		if lang := rec.Header().Get("Content-Language"); lang != "pt-BR" {
+			t.Errorf("Expected Content-Language pt-BR, got '%s'", lang)
+		}
+		if vary := rec.Header().Values("Vary"); !slices.Contains(vary, "Accept-Language") {
+			t.Errorf("Expected Vary to include Accept-Language, got %v", vary)
+		}
+
+		var body RestErr
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if body.Message != "usuário 42 não encontrado" {
+			t.Errorf("Expected translated message, got '%s'", body.Message)
+		}
+		if strings.Contains(rec.Body.String(), "user.not_found") {
+			t.Errorf("Expected the key not to be exposed, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("fallback language", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		rec := httptest.NewRecorder()
+		wr.Write(rec, req, restErr)
+
+		if lang := rec.Header().Get("Content-Language"); lang != "en" {
+			t.Errorf("Expected Content-Language en, got '%s'", lang)
+		}
+	})
+
+	t.Run("untranslated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", "pt-BR")
+		rec := httptest.NewRecorder()
+		wr.Write(rec, req, NewInternalServerError("boom"))
+
+		if lang := rec.Header().Get("Content-Language"); lang != "" {
+			t.Errorf("Expected no Content-Language, got '%s'", lang)
+		}
+	})
+}
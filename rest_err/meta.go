@@ -0,0 +1,100 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// Meta holds structured extension metadata clients can read programmatically,
+// e.g. {"quota_limit": 100, "resource_id": "abc"}. Values must be encodable with encoding/json
+type Meta map[string]any
+
+// MetaPlacement controls where Meta entries are encoded in JSON documents
+type MetaPlacement int
+
+const (
+	// MetaNested encodes Meta as a "meta" object
+	MetaNested MetaPlacement = iota
+	// MetaTopLevel encodes each entry as a top level member, problem+json style.
+	// Entries named like a RestErr member are left out
+	MetaTopLevel
+)
+
+// WithMeta returns a copy of the error with a metadata entry set
+func (r *RestErr) WithMeta(key string, value any) *RestErr {
+	clone := r.Clone()
+	if clone.Meta == nil {
+		clone.Meta = Meta{}
+	}
+	clone.Meta[key] = value
+	return clone
+}
+
+// Meta sets a metadata entry
+func (b Builder) Meta(key string, value any) Builder {
+	b.err.Meta = maps.Clone(b.err.Meta)
+	if b.err.Meta == nil {
+		b.err.Meta = Meta{}
+	}
+	b.err.Meta[key] = value
+	return b
+}
+
+// MetaValue returns the metadata entry key as a T. Values decoded from JSON are generic
+// (float64, map[string]any, ...) and are converted through encoding/json, so
+// MetaValue[int](err, "quota_limit") works on both sides of the wire
+func MetaValue[T any](r *RestErr, key string) (T, bool) {
+	var zero T
+	value, ok := r.Meta[key]
+	if !ok {
+		return zero, false
+	}
+	return convertJSON[T](value)
+}
+
+// convertJSON returns value as a T, converting generic JSON values through encoding/json
+func convertJSON[T any](value any) (T, bool) {
+	if typed, ok := value.(T); ok {
+		return typed, true
+	}
+
+	var zero, converted T
+	data, err := json.Marshal(value)
+	if err != nil {
+		return zero, false
+	}
+	if err := json.Unmarshal(data, &converted); err != nil {
+		return zero, false
+	}
+	return converted, true
+}
+
+// addMeta adds the entries of meta in key order, skipping reserved member names
+func (o *jsonObject) addMeta(meta Meta, reserved []string) {
+	for _, key := range slices.Sorted(maps.Keys(meta)) {
+		if !slices.Contains(reserved, key) {
+			o.add(key, meta[key])
+		}
+	}
+}
+
+// decodeMeta returns the members of the JSON object data that are not known members
+func decodeMeta(data []byte, known []string) (Meta, error) {
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	var meta Meta
+	for key, value := range members {
+		if slices.Contains(known, key) {
+			continue
+		}
+		if meta == nil {
+			meta = Meta{}
+		}
+		meta[key] = value
+	}
+	return meta, nil
+}
@@ -0,0 +1,129 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type quotaMeta struct {
+	Limit int `json:"limit"`
+	Used  int `json:"used"`
+}
+
+func TestRestErr_WithMeta(t *testing.T) {
+	sentinel := NewTooManyRequestsError("quota exceeded")
+	err := sentinel.WithMeta("quota_limit", 100).WithMeta("resource_id", "abc")
+
+	if sentinel.Meta != nil {
+		t.Error("Expected sentinel to be untouched")
+	}
+	if len(err.Meta) != 2 {
+		t.Errorf("Expected 2 entries, got %v", err.Meta)
+	}
+
+	clone := err.Clone()
+	clone.Meta["resource_id"] = "changed"
+	if err.Meta["resource_id"] != "abc" {
+		t.Error("Expected Clone to copy the Meta map")
+	}
+}
+
+func TestBuilder_Meta(t *testing.T) {
+	base := Build(http.StatusTooManyRequests).Meta("quota_limit", 100)
+	first := base.Meta("resource_id", "a").Err()
+	second := base.Err()
+
+	if len(first.Meta) != 2 || len(second.Meta) != 1 {
+		t.Errorf("Expected builders not to share Meta, got %v and %v", first.Meta, second.Meta)
+	}
+}
+
+func TestMetaValue(t *testing.T) {
+	err := NewTooManyRequestsError("quota exceeded").
+		WithMeta("quota_limit", 100).
+		WithMeta("quota", quotaMeta{Limit: 100, Used: 120})
+
+	if limit, ok := MetaValue[int](err, "quota_limit"); !ok || limit != 100 {
+		t.Errorf("Expected 100, got %v (%v)", limit, ok)
+	}
+	if _, ok := MetaValue[string](err, "quota_limit"); ok {
+		t.Error("Expected conversion of a number to string to fail")
+	}
+	if _, ok := MetaValue[int](err, "missing"); ok {
+		t.Error("Expected missing key to report false")
+	}
+
+	t.Run("decoded values", func(t *testing.T) {
+		data, _ := json.Marshal(err)
+		var decoded RestErr
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if limit, ok := MetaValue[int](&decoded, "quota_limit"); !ok || limit != 100 {
+			t.Errorf("Expected 100, got %v (%v)", limit, ok)
+		}
+		if quota, ok := MetaValue[quotaMeta](&decoded, "quota"); !ok || quota.Used != 120 {
+			t.Errorf("Expected typed struct, got %+v (%v)", quota, ok)
+		}
+	})
+}
+
+func TestMetaPlacement(t *testing.T) {
+	restErr := NewTooManyRequestsError("quota exceeded").
+		WithMeta("quota_limit", 100).
+		WithMeta("code", "ignored")
+
+	t.Run("nested by default", func(t *testing.T) {
+		data, _ := json.Marshal(restErr)
+		if !strings.Contains(string(data), `"meta":{"code":"ignored","quota_limit":100}`) {
+			t.Errorf("Expected nested meta object, got %s", data)
+		}
+	})
+
+	t.Run("top level", func(t *testing.T) {
+		enc := &Encoder{MetaPlacement: MetaTopLevel, Omit: []string{"timestamp"}}
+		data, err := enc.Marshal(restErr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `{"message":"quota exceeded","error":"too many requests","code":429,"quota_limit":100}`
+		if string(data) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+		}
+
+		decoded, err := enc.Unmarshal(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(decoded.Meta) != 1 || decoded.Meta["quota_limit"] != float64(100) {
+			t.Errorf("Expected top level members to be decoded into Meta, got %v", decoded.Meta)
+		}
+	})
+
+	t.Run("problem json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", MediaTypeProblemJSON)
+		rec := httptest.NewRecorder()
+		WriteError(rec, req, NewTooManyRequestsError("quota exceeded").WithMeta("quota_limit", 100).WithMeta("status", "ignored"))
+
+		body := rec.Body.String()
+		if !strings.Contains(body, `"quota_limit":100`) || strings.Contains(body, `"ignored"`) {
+			t.Errorf("Expected top level extension member, got %s", body)
+		}
+
+		var problem Problem
+		if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if problem.Meta["quota_limit"] != float64(100) || len(problem.Meta) != 1 {
+			t.Errorf("Expected extension members in Meta, got %v", problem.Meta)
+		}
+		if limit, ok := MetaValue[int](problem.ToRestErr(), "quota_limit"); !ok || limit != 100 {
+			t.Errorf("Expected Meta to survive ToRestErr, got %v", limit)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package rest_err
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histogram buckets
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics counts the errors written by a Writer by status, application code and route, and
+// records the latency of the requests they ended. It serves them in the Prometheus text
+// exposition format:
+//
+//	metrics := rest_err.NewMetrics()
+//	rest_err.DefaultWriter = rest_err.NewWriter(rest_err.WithMetrics(metrics))
+//	mux.Handle("GET /metrics", metrics)
+//	http.ListenAndServe(":8080", metrics.Middleware(mux))
+//
+// Routes are the ServeMux patterns that matched the requests, never raw paths, to keep the number of series bounded
+type Metrics struct {
+	mu      sync.Mutex
+	buckets []float64
+	series  map[metricLabels]*metricSeries
+}
+
+type metricLabels struct {
+	status  int
+	appCode string
+	route   string
+}
+
+type metricSeries struct {
+	errors   uint64
+	buckets  []uint64 // Cumulative counts per bucket
+	sum      float64
+	observed uint64
+}
+
+type requestStartKey struct{}
+
+// NewMetrics returns empty metrics with the given latency buckets, DefaultLatencyBuckets when none are given
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &Metrics{buckets: slices.Compact(buckets), series: map[metricLabels]*metricSeries{}}
+}
+
+// WithMetrics records the errors written by the Writer in m
+func WithMetrics(m *Metrics) WriterOption {
+	return func(wr *Writer) {
+		wr.metrics = m
+	}
+}
+
+// Middleware records when requests start so the latency of errored requests can be observed.
+// Without it errors are counted but no latency is recorded
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), requestStartKey{}, time.Now())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Observe records an error written in response to r
+func (m *Metrics) Observe(restErr *RestErr, r *http.Request) {
+	labels := metricLabels{status: restErr.Code, appCode: restErr.AppCode, route: r.Pattern}
+	start, timed := r.Context().Value(requestStartKey{}).(time.Time)
+	seconds := time.Since(start).Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.series[labels]
+	if !ok {
+		s = &metricSeries{buckets: make([]uint64, len(m.buckets))}
+		m.series[labels] = s
+	}
+	s.errors++
+	if !timed {
+		return
+	}
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.sum += seconds
+	s.observed++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WriteText(w)
+}
+
+// WriteText writes the metrics in the Prometheus text exposition format
+func (m *Metrics) WriteText(w io.Writer) error {
+	m.mu.Lock()
+	labels := slices.SortedFunc(maps.Keys(m.series), func(a, b metricLabels) int {
+		return cmp.Or(cmp.Compare(a.route, b.route), cmp.Compare(a.status, b.status), cmp.Compare(a.appCode, b.appCode))
+	})
+	series := make([]metricSeries, len(labels))
+	for i, l := range labels {
+		series[i] = *m.series[l]
+		series[i].buckets = slices.Clone(series[i].buckets)
+	}
+	m.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP rest_err_errors_total Errors written, by status, application code and route.\n")
+	b.WriteString("# TYPE rest_err_errors_total counter\n")
+	for i, l := range labels {
+		fmt.Fprintf(&b, "rest_err_errors_total{%s} %d\n", l.format(), series[i].errors)
+	}
+
+	b.WriteString("# HELP rest_err_request_duration_seconds Latency of the requests that ended in an error.\n")
+	b.WriteString("# TYPE rest_err_request_duration_seconds histogram\n")
+	for i, l := range labels {
+		s := series[i]
+		if s.observed == 0 {
+			continue
+		}
+		for j, bound := range m.buckets {
+			fmt.Fprintf(&b, "rest_err_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", l.format(), formatFloat(bound), s.buckets[j])
+		}
+		fmt.Fprintf(&b, "rest_err_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", l.format(), s.observed)
+		fmt.Fprintf(&b, "rest_err_request_duration_seconds_sum{%s} %s\n", l.format(), formatFloat(s.sum))
+		fmt.Fprintf(&b, "rest_err_request_duration_seconds_count{%s} %d\n", l.format(), s.observed)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (l metricLabels) format() string {
+	return fmt.Sprintf(`status="%d",app_code="%s",route="%s"`, l.status, labelEscaper.Replace(l.appCode), labelEscaper.Replace(l.route))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,67 @@
+package rest_err
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics(0.1, 0.05, 1)
+	wr := NewWriter(WithMetrics(metrics))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		wr.Write(w, r, NewNotFoundError("user %s not found", r.PathValue("id")).WithAppCode("USER_NOT_FOUND"))
+	})
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+		wr.Write(w, r, NewConflictError("taken").WithAppCode(`EMAIL"TAKEN`))
+	})
+	handler := metrics.Middleware(mux)
+
+	for _, path := range []string{"/users/1", "/users/2"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+	wr.Write(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/untimed", nil), NewInternalServerError("boom"))
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Expected Prometheus text content type, got '%s'", ct)
+	}
+
+	body := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE rest_err_errors_total counter\n",
+		`rest_err_errors_total{status="500",app_code="",route=""} 1` + "\n",
+		`rest_err_errors_total{status="404",app_code="USER_NOT_FOUND",route="GET /users/{id}"} 2` + "\n",
+		`rest_err_errors_total{status="409",app_code="EMAIL\"TAKEN",route="POST /users"} 1` + "\n",
+		"# TYPE rest_err_request_duration_seconds histogram\n",
+		`rest_err_request_duration_seconds_bucket{status="404",app_code="USER_NOT_FOUND",route="GET /users/{id}",le="0.05"} 2` + "\n",
+		`rest_err_request_duration_seconds_bucket{status="404",app_code="USER_NOT_FOUND",route="GET /users/{id}",le="+Inf"} 2` + "\n",
+		`rest_err_request_duration_seconds_count{status="404",app_code="USER_NOT_FOUND",route="GET /users/{id}"} 2` + "\n",
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("Expected line %q in\n%s", line, body)
+		}
+	}
+
+	if strings.Contains(body, `rest_err_request_duration_seconds_count{status="500"`) {
+		t.Error("Expected no latency for requests outside the middleware")
+	}
+	if strings.Index(body, `le="0.05"`) > strings.Index(body, `le="0.1"`) {
+		t.Error("Expected buckets to be sorted")
+	}
+}
+
+func TestMetrics_Empty(t *testing.T) {
+	var b strings.Builder
+	if err := NewMetrics().WriteText(&b); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(b.String(), "{") {
+		t.Errorf("Expected no series, got\n%s", b.String())
+	}
+}
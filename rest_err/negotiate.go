@@ -0,0 +1,116 @@
+package rest_err
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Media types a RestErr can be rendered as
+const (
+	MediaTypeJSON        = "application/json"
+	MediaTypeProblemJSON = "application/problem+json"
+	MediaTypeXML         = "application/xml"
+	MediaTypeProblemXML  = "application/problem+xml"
+	MediaTypeText        = "text/plain"
+	MediaTypeHTML        = "text/html"
+)
+
+// qualityValue is a single entry of a header such as Accept or Accept-Language
+type qualityValue struct {
+	value string
+	q     float64
+}
+
+// parseQualityList parses a comma separated header with optional ;q= weights.
+// Entries are returned in header order with parameters other than q dropped
+func parseQualityList(header string) []qualityValue {
+	var values []qualityValue
+	for _, part := range strings.Split(header, ",") {
+		value, params, _ := strings.Cut(part, ";")
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, raw, ok := strings.Cut(param, "=")
+			if !ok || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(raw), 64); err == nil && parsed >= 0 && parsed <= 1 {
+				q = parsed
+			}
+		}
+		values = append(values, qualityValue{value: value, q: q})
+	}
+	return values
+}
+
+// negotiateMediaType picks the offer preferred by the Accept header.
+// Offers are matched against the most specific media range; ties in quality are broken by
+// specificity and then by offer order. An empty header selects the first offer. When the header
+// accepts no offer, the result falls back to JSON, or to the first offer when JSON is not offered,
+// skipping offers refused with q=0. False is returned only when every offer is refused with q=0
+func negotiateMediaType(accept string, offers []string) (string, bool) {
+	ranges := parseQualityList(accept)
+	if len(ranges) == 0 {
+		return offers[0], true
+	}
+
+	best, bestQ, bestSpecificity := "", 0.0, -1
+	var allowed []string // Offers not refused with q=0
+	for _, offer := range offers {
+		q, specificity := matchMediaRange(ranges, offer)
+		if specificity >= 0 && q <= 0 {
+			continue
+		}
+		allowed = append(allowed, offer)
+		if specificity < 0 {
+			continue
+		}
+		if q > bestQ || (q == bestQ && specificity > bestSpecificity) {
+			best, bestQ, bestSpecificity = offer, q, specificity
+		}
+	}
+	switch {
+	case best != "":
+		return best, true
+	case len(allowed) == 0:
+		return "", false
+	case slices.Contains(allowed, MediaTypeJSON):
+		return MediaTypeJSON, true
+	}
+	return allowed[0], true
+}
+
+// matchMediaRange returns the quality of the most specific range matching offer and that specificity:
+// 2 for type/subtype, 1 for type/* and 0 for */*, -1 when nothing matches
+func matchMediaRange(ranges []qualityValue, offer string) (float64, int) {
+	offerType, offerSub, _ := strings.Cut(offer, "/")
+
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		rangeType, rangeSub, _ := strings.Cut(r.value, "/")
+
+		s := -1
+		switch {
+		case rangeType == offerType && rangeSub == offerSub:
+			s = 2
+		case rangeType == offerType && rangeSub == "*":
+			s = 1
+		case rangeType == "*" && rangeSub == "*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q, specificity
+}
+
+// isSupportedMediaType reports whether mediaType can be rendered by the Writer
+func isSupportedMediaType(mediaType string) bool {
+	return slices.Contains([]string{MediaTypeJSON, MediaTypeProblemJSON, MediaTypeXML, MediaTypeProblemXML, MediaTypeText, MediaTypeHTML}, mediaType)
+}
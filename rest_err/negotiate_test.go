@@ -0,0 +1,64 @@
+package rest_err
+
+import "testing"
+
+func TestNegotiateMediaType(t *testing.T) {
+	offers := []string{MediaTypeJSON, MediaTypeProblemJSON, MediaTypeXML, MediaTypeText, MediaTypeHTML}
+
+	tests := []struct {
+		name     string
+		accept   string
+		expected string
+		ok       bool
+	}{
+		{"empty header", "", MediaTypeJSON, true},
+		{"wildcard", "*/*", MediaTypeJSON, true},
+		{"exact match", "application/xml", MediaTypeXML, true},
+		{"problem json", "application/problem+json", MediaTypeProblemJSON, true},
+		{"browser", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", MediaTypeHTML, true},
+		{"quality ordering", "text/plain;q=0.5, application/xml;q=0.8", MediaTypeXML, true},
+		{"type wildcard", "text/*", MediaTypeText, true},
+		{"specific beats wildcard", "text/*;q=0.9, text/html;q=0.9", MediaTypeHTML, true},
+		{"specific refusal", "*/*, application/json;q=0", MediaTypeProblemJSON, true},
+		{"case insensitive", "Application/XML", MediaTypeXML, true},
+		{"unsupported only", "image/png", MediaTypeJSON, true},
+		{"everything refused", "*/*;q=0", "", false},
+		{"one type refused", "application/json;q=0", MediaTypeProblemJSON, true},
+		{"unsupported and one type refused", "image/png, application/json;q=0", MediaTypeProblemJSON, true},
+		{"every offer refused", "application/json;q=0, application/problem+json;q=0, application/xml;q=0, text/*;q=0", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := negotiateMediaType(tt.accept, offers)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tt.expected, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestNegotiateMediaType_WithoutJSON(t *testing.T) {
+	offers := []string{MediaTypeXML, MediaTypeText}
+
+	if got, ok := negotiateMediaType("image/png", offers); got != MediaTypeXML || !ok {
+		t.Errorf("Expected the first offer, got (%q, %v)", got, ok)
+	}
+	if got, ok := negotiateMediaType("image/png, application/xml;q=0", offers); got != MediaTypeText || !ok {
+		t.Errorf("Expected the first offer not refused, got (%q, %v)", got, ok)
+	}
+}
+
+func TestParseQualityList(t *testing.T) {
+	values := parseQualityList("pt-BR, pt;q=0.8, en;q=abc, , es;level=1;q=0.2")
+	expected := []qualityValue{{"pt-br", 1}, {"pt", 0.8}, {"en", 1}, {"es", 0.2}}
+
+	if len(values) != len(expected) {
+		t.Fatalf("Expected %d values, got %+v", len(expected), values)
+	}
+	for i, v := range expected {
+		if values[i] != v {
+			t.Errorf("Expected %+v, got %+v", v, values[i])
+		}
+	}
+}
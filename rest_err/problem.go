@@ -0,0 +1,111 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Problem is the RFC 9457 problem details representation of a RestErr,
+// served as application/problem+json. AppCode, Causes, Details, Timestamp and every Meta entry are extension members
+type Problem struct {
+	Type      string    `json:"type,omitempty" example:"about:blank"`                  // URI identifying the problem type
+	Title     string    `json:"title" example:"Bad Request"`                           // Short summary of the problem type
+	Status    int       `json:"status" example:"400"`                                  // HTTP status code
+	Detail    string    `json:"detail,omitempty" example:"invalid request parameters"` // Human readable explanation of this occurrence
+	Instance  string    `json:"instance,omitempty" example:"/users/42"`                // URI reference identifying this occurrence
+	AppCode   string    `json:"app_code,omitempty" example:"USER_NOT_FOUND"`           // Application specific error code
+	Causes    []Causes  `json:"causes,omitempty"`                                      // Detailed error causes
+	Details   any       `json:"details,omitempty"`                                     // Typed detail payload
+	Timestamp time.Time `json:"timestamp"`                                             // When the error occurred
+	Meta      Meta      `json:"-"`                                                     // Additional extension members
+}
+
+// ToProblem converts the error to problem details, Type is left empty which RFC 9457 treats as "about:blank"
+func (r *RestErr) ToProblem() *Problem {
+	title := http.StatusText(r.Code)
+	if title == "" {
+		title = r.Err
+	}
+	return &Problem{
+		Title:     title,
+		Status:    r.Code,
+		Detail:    r.Message,
+		AppCode:   r.AppCode,
+		Causes:    r.Causes,
+		Details:   r.Details,
+		Timestamp: r.Timestamp,
+		Meta:      r.Meta,
+	}
+}
+
+// ToRestErr converts problem details, e.g. decoded from an upstream response, back to a RestErr
+func (p *Problem) ToRestErr() *RestErr {
+	return &RestErr{
+		Message:   p.Detail,
+		Err:       errText(p.Status),
+		Code:      p.Status,
+		AppCode:   p.AppCode,
+		Causes:    p.Causes,
+		Details:   p.Details,
+		Timestamp: p.Timestamp,
+		Meta:      p.Meta,
+	}
+}
+
+// problemMembers are the members of Problem that Meta entries cannot replace
+var problemMembers = []string{"type", "title", "status", "detail", "instance", "app_code", "causes", "details", "timestamp"}
+
+// MarshalJSON encodes the problem with the timestamp in the package format and Meta as top level members
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	var obj jsonObject
+	if p.Type != "" {
+		obj.add("type", p.Type)
+	}
+	obj.add("title", p.Title)
+	obj.add("status", p.Status)
+	if p.Detail != "" {
+		obj.add("detail", p.Detail)
+	}
+	if p.Instance != "" {
+		obj.add("instance", p.Instance)
+	}
+	if p.AppCode != "" {
+		obj.add("app_code", p.AppCode)
+	}
+	if len(p.Causes) > 0 {
+		obj.add("causes", p.Causes)
+	}
+	if p.Details != nil {
+		obj.add("details", p.Details)
+	}
+	if ts := encodeTimestamp(p.Timestamp, TimestampDefault); ts != nil {
+		obj.add("timestamp", ts)
+	}
+	obj.addMeta(p.Meta, problemMembers)
+	return obj.bytes(), obj.err
+}
+
+// UnmarshalJSON decodes a problem, accepting every TimestampFormat. Unknown members are kept in Meta
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	type problem Problem
+	aux := struct {
+		*problem
+		Timestamp json.RawMessage `json:"timestamp"`
+	}{problem: (*problem)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	ts, err := decodeTimestamp(aux.Timestamp)
+	if err != nil {
+		return err
+	}
+	p.Timestamp = ts
+
+	meta, err := decodeMeta(data, problemMembers)
+	if err != nil {
+		return err
+	}
+	p.Meta = meta
+	return nil
+}
@@ -0,0 +1,40 @@
+package rest_err
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRestErr_ToProblem(t *testing.T) {
+	restErr := NewConflictValidationError("email already taken", []Causes{{Field: "email", Message: "duplicate"}})
+	problem := restErr.ToProblem()
+
+	if problem.Title != "Conflict" {
+		t.Errorf("Expected title 'Conflict', got '%s'", problem.Title)
+	}
+	if problem.Status != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", problem.Status)
+	}
+	if problem.Detail != "email already taken" {
+		t.Errorf("Expected detail to be the message, got '%s'", problem.Detail)
+	}
+	if len(problem.Causes) != 1 || !problem.Timestamp.Equal(restErr.Timestamp) {
+		t.Errorf("Expected causes and timestamp to be kept, got %+v", problem)
+	}
+
+	t.Run("non standard status", func(t *testing.T) {
+		problem := NewRestErr("client closed request", "client closed request", 499, nil).ToProblem()
+		if problem.Title != "client closed request" {
+			t.Errorf("Expected title to fall back to Err, got '%s'", problem.Title)
+		}
+	})
+}
+
+func TestProblem_ToRestErr(t *testing.T) {
+	problem := &Problem{Title: "Not Found", Status: http.StatusNotFound, Detail: "user not found"}
+	restErr := problem.ToRestErr()
+
+	if restErr.Code != http.StatusNotFound || restErr.Err != "not found" || restErr.Message != "user not found" {
+		t.Errorf("Unexpected RestErr %+v", restErr)
+	}
+}
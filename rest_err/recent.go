@@ -0,0 +1,186 @@
+package rest_err
+
+import (
+	"container/list"
+	"encoding/json"
+	"expvar"
+	"html/template"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultRecentErrorsSize is the number of distinct errors kept by NewRecentErrors when size is not positive
+const DefaultRecentErrorsSize = 100
+
+// RecentError is an entry of RecentErrors, grouping the occurrences of an error by fingerprint
+type RecentError struct {
+	Fingerprint string    `json:"fingerprint" example:"9f86d081884c7d65"`
+	Count       uint64    `json:"count" example:"3"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	Path        string    `json:"path,omitempty" example:"/users/42"`        // Request path of the last occurrence
+	Route       string    `json:"route,omitempty" example:"GET /users/{id}"` // ServeMux pattern of the last occurrence
+	Error       *RestErr  `json:"error"`                                     // Last occurrence
+}
+
+// RecentErrors keeps the last written errors, grouped by fingerprint, for debugging live
+// instances. It holds at most size distinct errors, evicting the least recently seen one.
+// It is a Reporter, register it with AddHook, and serves the entries as HTML or JSON:
+//
+//	recent := rest_err.NewRecentErrors(100)
+//	rest_err.AddHook(recent)
+//	recent.Publish("rest_err_recent")
+//	mux.Handle("GET /debug/errors", recent)
+//
+// Entries hold the same data the error responses exposed: wrapped errors are dropped,
+// and WithRedaction can hide more, e.g. the message of 5xx errors
+type RecentErrors struct {
+	mu      sync.Mutex
+	size    int
+	redact  func(*RestErr) *RestErr
+	clock   Clock
+	lru     *list.List               // *RecentError, most recently seen first
+	entries map[string]*list.Element // by fingerprint
+}
+
+// RecentErrorsOption configures RecentErrors
+type RecentErrorsOption func(*RecentErrors)
+
+// WithRedaction sets a function applied to each error before it is kept. It receives a copy
+// it can modify, returning nil skips the error
+func WithRedaction(fn func(*RestErr) *RestErr) RecentErrorsOption {
+	return func(re *RecentErrors) {
+		re.redact = fn
+	}
+}
+
+// WithRecentClock sets the clock first and last seen times are read from, time.Now by default.
+// It is independent of the package clock, so a fixed SetClock does not freeze them
+func WithRecentClock(c Clock) RecentErrorsOption {
+	return func(re *RecentErrors) {
+		if c != nil {
+			re.clock = c
+		}
+	}
+}
+
+// NewRecentErrors returns an empty buffer of at most size distinct errors
+func NewRecentErrors(size int, opts ...RecentErrorsOption) *RecentErrors {
+	if size <= 0 {
+		size = DefaultRecentErrorsSize
+	}
+	re := &RecentErrors{size: size, clock: ClockFunc(time.Now), lru: list.New(), entries: map[string]*list.Element{}}
+	for _, opt := range opts {
+		opt(re)
+	}
+	return re
+}
+
+// Report records a reported error
+func (re *RecentErrors) Report(rep Report) {
+	var path, route string
+	if rep.Request != nil {
+		path, route = rep.Request.Path, rep.Request.Route
+	}
+	re.Record(rep.Err, path, route)
+}
+
+// Record records an occurrence of restErr, written in response to a request for path and route
+func (re *RecentErrors) Record(restErr *RestErr, path, route string) {
+	fingerprint := restErr.Fingerprint()
+
+	kept := restErr.Clone()
+	kept.Wrapped = nil
+	if re.redact != nil {
+		if kept = re.redact(kept); kept == nil {
+			return
+		}
+	}
+	seen := re.clock.Now()
+
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	if elem, ok := re.entries[fingerprint]; ok {
+		entry := elem.Value.(*RecentError)
+		entry.Count++
+		entry.LastSeen, entry.Path, entry.Route, entry.Error = seen, path, route, kept
+		re.lru.MoveToFront(elem)
+		return
+	}
+
+	re.entries[fingerprint] = re.lru.PushFront(&RecentError{
+		Fingerprint: fingerprint,
+		Count:       1,
+		FirstSeen:   seen,
+		LastSeen:    seen,
+		Path:        path,
+		Route:       route,
+		Error:       kept,
+	})
+	if re.lru.Len() > re.size {
+		oldest := re.lru.Back()
+		re.lru.Remove(oldest)
+		delete(re.entries, oldest.Value.(*RecentError).Fingerprint)
+	}
+}
+
+// Entries returns copies of the entries, most recently seen first
+func (re *RecentErrors) Entries() []RecentError {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	entries := make([]RecentError, 0, re.lru.Len())
+	for elem := re.lru.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, *elem.Value.(*RecentError))
+	}
+	return entries
+}
+
+// Publish publishes the entries as an expvar variable. Like expvar.Publish it panics when name is already in use
+func (re *RecentErrors) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return re.Entries()
+	}))
+}
+
+// ServeHTTP serves the entries as JSON or as an HTML page, negotiated from the Accept header
+func (re *RecentErrors) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mediaType, ok := negotiateMediaType(r.Header.Get("Accept"), []string{MediaTypeJSON, MediaTypeHTML})
+	if !ok {
+		mediaType = MediaTypeJSON
+	}
+
+	h := w.Header()
+	h.Add("Vary", "Accept")
+	h.Set("Cache-Control", "no-store")
+	h.Set("X-Content-Type-Options", "nosniff")
+
+	entries := re.Entries()
+	if mediaType == MediaTypeHTML {
+		h.Set("Content-Type", MediaTypeHTML+"; charset=utf-8")
+		_ = recentErrorsTemplate.Execute(w, entries)
+		return
+	}
+	h.Set("Content-Type", MediaTypeJSON)
+	_ = json.NewEncoder(w).Encode(entries)
+}
+
+var recentErrorsTemplate = template.Must(template.New("recent").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Recent errors</title>
+</head>
+<body>
+<h1>Recent errors</h1>
+<table>
+<tr><th>Fingerprint</th><th>Count</th><th>Status</th><th>Code</th><th>Message</th><th>Route</th><th>Path</th><th>First seen</th><th>Last seen</th></tr>
+{{- range .}}
+<tr><td>{{.Fingerprint}}</td><td>{{.Count}}</td><td>{{.Error.Code}}</td><td>{{.Error.AppCode}}</td><td>{{.Error.Message}}</td><td>{{.Route}}</td><td>{{.Path}}</td><td>{{.FirstSeen.Format "2006-01-02T15:04:05Z07:00"}}</td><td>{{.LastSeen.Format "2006-01-02T15:04:05Z07:00"}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,175 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecentErrors_Record(t *testing.T) {
+	clock := fixedTime
+	recent := NewRecentErrors(2, WithRecentClock(ClockFunc(func() time.Time { return clock })))
+	newErr := func(id int) *RestErr {
+		return NewNotFoundError("user %d not found", id).WithCause(errors.New("sql: no rows"))
+	}
+
+	recent.Record(newErr(1), "/users/1", "GET /users/{id}")
+	clock = clock.Add(time.Minute)
+	recent.Record(newErr(2), "/users/2", "GET /users/{id}")
+
+	entries := recent.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected occurrences to be grouped, got %d entries", len(entries))
+	}
+	entry := entries[0]
+	if entry.Count != 2 || entry.Path != "/users/2" || entry.Error.Message != "user 2 not found" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if !entry.FirstSeen.Equal(fixedTime) || !entry.LastSeen.Equal(fixedTime.Add(time.Minute)) {
+		t.Errorf("Unexpected first/last seen %v %v", entry.FirstSeen, entry.LastSeen)
+	}
+	if entry.Error.Wrapped != nil {
+		t.Error("Expected wrapped errors not to be kept")
+	}
+
+	t.Run("bounded", func(t *testing.T) {
+		recent.Record(NewConflictError("taken"), "/users", "POST /users")
+		recent.Record(newErr(3), "/users/3", "GET /users/{id}")
+		recent.Record(NewBadRequestError("bad"), "/users", "POST /users")
+
+		entries := recent.Entries()
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(entries))
+		}
+		if entries[0].Error.Code != http.StatusBadRequest || entries[1].Error.Code != http.StatusNotFound {
+			t.Errorf("Expected the least recently seen entry to be evicted, got %d and %d", entries[0].Error.Code, entries[1].Error.Code)
+		}
+	})
+}
+
+func TestRecentErrors_PackageClock(t *testing.T) {
+	resetTimestamps(t)
+	SetClock(ClockFunc(func() time.Time { return fixedTime }))
+
+	recent := NewRecentErrors(10)
+	recent.Record(NewNotFoundError("missing"), "/", "")
+	if entries := recent.Entries(); entries[0].FirstSeen.Equal(fixedTime) {
+		t.Error("Expected seen times not to follow the package clock")
+	}
+}
+
+func TestRecentErrors_Redaction(t *testing.T) {
+	recent := NewRecentErrors(10, WithRedaction(func(r *RestErr) *RestErr {
+		if r.Code == http.StatusUnauthorized {
+			return nil
+		}
+		if r.IsServerError() {
+			r.Message = "redacted"
+		}
+		return r
+	}))
+
+	original := NewInternalServerError("db password invalid")
+	recent.Record(original, "/", "")
+	recent.Record(NewUnauthorizedError("bad token"), "/", "")
+
+	entries := recent.Entries()
+	if len(entries) != 1 || entries[0].Error.Message != "redacted" {
+		t.Errorf("Expected redacted entries, got %+v", entries)
+	}
+	if original.Message != "db password invalid" {
+		t.Error("Expected redaction not to modify the original error")
+	}
+}
+
+func TestRecentErrors_Hook(t *testing.T) {
+	recent := NewRecentErrors(10)
+	hook := AddHook(recent)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, r, NewNotFoundError("order not found"))
+	})
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/7", nil))
+	hook.Close()
+
+	entries := recent.Entries()
+	if len(entries) != 1 || entries[0].Route != "GET /orders/{id}" || entries[0].Path != "/orders/7" {
+		t.Errorf("Expected the written error to be recorded, got %+v", entries)
+	}
+}
+
+func TestRecentErrors_ServeHTTP(t *testing.T) {
+	recent := NewRecentErrors(10)
+	recent.Record(NewBadRequestError("<script>alert(1)</script>"), "/search", "GET /search")
+
+	t.Run("json", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		recent.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/errors", nil))
+
+		if ct := rec.Header().Get("Content-Type"); ct != MediaTypeJSON {
+			t.Errorf("Expected JSON, got '%s'", ct)
+		}
+		var entries []RecentError
+		if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Count != 1 || entries[0].Error.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected entries %+v", entries)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/debug/errors", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+		rec := httptest.NewRecorder()
+		recent.ServeHTTP(rec, req)
+
+		body := rec.Body.String()
+		if !strings.HasPrefix(rec.Header().Get("Content-Type"), MediaTypeHTML) || !strings.Contains(body, "GET /search") {
+			t.Errorf("Expected HTML page, got %s", body)
+		}
+		if strings.Contains(body, "<script>") {
+			t.Error("Expected messages to be escaped")
+		}
+	})
+}
+
+func TestRecentErrors_Publish(t *testing.T) {
+	recent := NewRecentErrors(10)
+	recent.Publish("rest_err_recent_test")
+	recent.Record(NewNotFoundError("missing"), "/", "")
+
+	v := expvar.Get("rest_err_recent_test")
+	if v == nil || !strings.Contains(v.String(), `"count":1`) {
+		t.Errorf("Expected published entries, got %v", v)
+	}
+}
+
+func TestRecentErrors_Concurrent(t *testing.T) {
+	recent := NewRecentErrors(5)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				recent.Record(NewNotFoundError("missing"), "/", "")
+				_ = recent.Entries()
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries := recent.Entries()
+	if len(entries) != 1 || entries[0].Count != 1000 {
+		t.Errorf("Expected 1000 grouped occurrences, got %+v", entries)
+	}
+}
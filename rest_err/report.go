@@ -0,0 +1,248 @@
+package rest_err
+
+import (
+	"net/http"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultReportQueueSize is the number of reports a Hook buffers before dropping new ones
+const DefaultReportQueueSize = 1024
+
+// ReportEvent tells reporters why an error is reported
+type ReportEvent int
+
+const (
+	// EventWritten reports errors written to a response by a Writer
+	EventWritten ReportEvent = iota
+	// EventCreated reports 5xx errors created by a constructor, Factory or Builder. They are
+	// reported once, when first written by a Writer or logged by a SampledLogger, so the report
+	// holds the final value, including causes attached with WithCause
+	EventCreated
+)
+
+// Report is what reporters receive for each reported error
+type Report struct {
+	Event   ReportEvent
+	Err     *RestErr
+	Request *RequestInfo    // Request the error was written to, nil for EventCreated
+	Stack   []runtime.Frame // Where the error was created, empty for decoded errors
+}
+
+// RequestInfo holds the request metadata of a report. It is copied when the error is
+// reported so reporters never touch the *http.Request after the handler returned
+type RequestInfo struct {
+	Method     string `json:"method" example:"GET"`
+	Path       string `json:"path" example:"/users/42"`
+	Route      string `json:"route,omitempty" example:"GET /users/{id}"` // ServeMux pattern that matched the request
+	Host       string `json:"host,omitempty"`
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	UserAgent  string `json:"user_agent,omitempty"`
+	RequestID  string `json:"request_id,omitempty"` // X-Request-Id header
+}
+
+// Reporter observes reported errors, e.g. to forward them to incident tooling
+type Reporter interface {
+	Report(Report)
+}
+
+// ReporterFunc adapts a function to the Reporter interface
+type ReporterFunc func(Report)
+
+// Report calls f
+func (f ReporterFunc) Report(rep Report) {
+	f(rep)
+}
+
+// ReporterStats holds the delivery counters of a Hook
+type ReporterStats struct {
+	Delivered uint64 // Reports handed to the reporter
+	Dropped   uint64 // Reports dropped because the queue was full
+	Failed    uint64 // Reports whose delivery panicked
+	Queued    int    // Reports waiting for delivery
+}
+
+// Hook is a registered reporter. Reports are queued and delivered by a goroutine of the hook,
+// in order, so a slow reporter never blocks a request; when its queue is full new reports are dropped
+type Hook struct {
+	reporter Reporter
+	events   []ReportEvent
+	queue    chan Report
+	quit     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	failed    atomic.Uint64
+}
+
+// HookOption configures a Hook
+type HookOption func(*Hook)
+
+// WithQueueSize sets the number of reports buffered by the hook, DefaultReportQueueSize by default
+func WithQueueSize(n int) HookOption {
+	return func(h *Hook) {
+		if n > 0 {
+			h.queue = make(chan Report, n)
+		}
+	}
+}
+
+// WithEvents sets the events reported to the hook, only EventWritten by default
+func WithEvents(events ...ReportEvent) HookOption {
+	return func(h *Hook) {
+		h.events = events
+	}
+}
+
+var hooks atomic.Pointer[[]*Hook]
+
+// AddHook registers a reporter and starts delivering reports to it until the hook is closed
+func AddHook(r Reporter, opts ...HookOption) *Hook {
+	h := &Hook{
+		reporter: r,
+		events:   []ReportEvent{EventWritten},
+		queue:    make(chan Report, DefaultReportQueueSize),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	updateHooks(func(current []*Hook) []*Hook {
+		return append(slices.Clip(current), h)
+	})
+	go h.run()
+	return h
+}
+
+// Close unregisters the hook and waits until the reports already queued are delivered
+func (h *Hook) Close() {
+	h.once.Do(func() {
+		updateHooks(func(current []*Hook) []*Hook {
+			return slices.DeleteFunc(slices.Clone(current), func(other *Hook) bool {
+				return other == h
+			})
+		})
+		close(h.quit)
+	})
+	<-h.done
+}
+
+// Stats returns the delivery counters of the hook
+func (h *Hook) Stats() ReporterStats {
+	return ReporterStats{
+		Delivered: h.delivered.Load(),
+		Dropped:   h.dropped.Load(),
+		Failed:    h.failed.Load(),
+		Queued:    len(h.queue),
+	}
+}
+
+func updateHooks(fn func([]*Hook) []*Hook) {
+	for {
+		current := hooks.Load()
+		var list []*Hook
+		if current != nil {
+			list = *current
+		}
+		updated := fn(list)
+		if hooks.CompareAndSwap(current, &updated) {
+			return
+		}
+	}
+}
+
+func (h *Hook) run() {
+	defer close(h.done)
+	for {
+		select {
+		case rep := <-h.queue:
+			h.deliver(rep)
+		case <-h.quit:
+			for {
+				select {
+				case rep := <-h.queue:
+					h.deliver(rep)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (h *Hook) deliver(rep Report) {
+	defer func() {
+		if recover() != nil {
+			h.failed.Add(1)
+		}
+	}()
+
+	rep.Stack = rep.Err.Stack()
+	h.reporter.Report(rep)
+	h.delivered.Add(1)
+}
+
+func (h *Hook) enqueue(rep Report) {
+	if !slices.Contains(h.events, rep.Event) {
+		return
+	}
+	select {
+	case h.queue <- rep:
+	default:
+		h.dropped.Add(1)
+	}
+}
+
+// report queues the error for every registered hook, it never blocks
+func report(event ReportEvent, restErr *RestErr, r *http.Request) {
+	current := hooks.Load()
+	if current == nil || len(*current) == 0 {
+		return
+	}
+
+	rep := Report{Event: event, Err: restErr}
+	if r != nil {
+		rep.Request = newRequestInfo(r)
+	}
+	for _, h := range *current {
+		h.enqueue(rep)
+	}
+}
+
+// creation tracks whether the creation of an error and its copies was reported
+type creation struct {
+	reported atomic.Bool
+}
+
+// created marks 5xx errors for an EventCreated report and returns the error
+func created(restErr *RestErr) *RestErr {
+	if restErr.Code >= 500 {
+		restErr.creation = &creation{}
+	}
+	return restErr
+}
+
+// reportCreated reports the creation of restErr unless it, or a copy, was already reported
+func reportCreated(restErr *RestErr) {
+	if restErr.creation != nil && restErr.creation.reported.CompareAndSwap(false, true) {
+		report(EventCreated, restErr, nil)
+	}
+}
+
+func newRequestInfo(r *http.Request) *RequestInfo {
+	return &RequestInfo{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Route:      r.Pattern,
+		Host:       r.Host,
+		RemoteAddr: r.RemoteAddr,
+		UserAgent:  r.UserAgent(),
+		RequestID:  r.Header.Get("X-Request-Id"),
+	}
+}
@@ -0,0 +1,134 @@
+package rest_err
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddHook_Written(t *testing.T) {
+	reports := make(chan Report, 10)
+	hook := AddHook(ReporterFunc(func(rep Report) { reports <- rep }))
+	defer hook.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, r, NewNotFoundError("user %s not found", r.PathValue("id")))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("X-Request-Id", "abc")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	select {
+	case rep := <-reports:
+		if rep.Event != EventWritten || rep.Err.Code != http.StatusNotFound {
+			t.Errorf("Unexpected report %+v", rep)
+		}
+		if rep.Request == nil || rep.Request.Path != "/users/42" || rep.Request.Route != "GET /users/{id}" || rep.Request.RequestID != "abc" {
+			t.Errorf("Unexpected request info %+v", rep.Request)
+		}
+		if len(rep.Stack) == 0 || !strings.Contains(rep.Stack[0].Function, "TestAddHook_Written") {
+			t.Errorf("Expected the stack of the creation site, got %+v", rep.Stack)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a report")
+	}
+}
+
+func TestAddHook_Created(t *testing.T) {
+	var mu sync.Mutex
+	var reports []Report
+	hook := AddHook(ReporterFunc(func(rep Report) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, rep)
+	}), WithEvents(EventCreated))
+
+	dbErr := errors.New("connection refused")
+	internal := NewInternalServerError("boom").WithCause(dbErr)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	NewInternalServerError("never written")
+	WriteError(w, r, internal)
+	WriteError(w, r, internal.WithMeta("retry", 2))
+	WriteError(w, r, NewNotFoundError("missing"))
+	logger, _ := newTestLogger(t)
+	NewSampledLogger(logger, time.Second).Log(context.Background(), Build(http.StatusBadGateway).Message("upstream failed").Err())
+	hook.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 2 || reports[0].Err.Code != http.StatusInternalServerError || reports[1].Err.Code != http.StatusBadGateway {
+		t.Fatalf("Expected 5xx creations to be reported once, got %+v", reports)
+	}
+	if reports[0].Err.Wrapped != dbErr {
+		t.Errorf("Expected the cause attached with WithCause, got %v", reports[0].Err.Wrapped)
+	}
+	if reports[0].Event != EventCreated || reports[0].Request != nil {
+		t.Errorf("Unexpected report %+v", reports[0])
+	}
+}
+
+func TestHook_Drops(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	hook := AddHook(ReporterFunc(func(rep Report) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+	}), WithQueueSize(1))
+
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil)
+	WriteError(w, r, NewNotFoundError("first"))
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			WriteError(httptest.NewRecorder(), r, NewNotFoundError("missing"))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected writes not to block on a slow reporter")
+	}
+
+	stats := hook.Stats()
+	if stats.Queued != 1 || stats.Dropped != 4 {
+		t.Errorf("Expected 1 queued and 4 dropped, got %+v", stats)
+	}
+
+	close(release)
+	hook.Close()
+	if stats := hook.Stats(); stats.Delivered != 2 || stats.Queued != 0 {
+		t.Errorf("Expected queued reports to be delivered on Close, got %+v", stats)
+	}
+}
+
+func TestHook_Close(t *testing.T) {
+	var calls int
+	hook := AddHook(ReporterFunc(func(rep Report) {
+		calls++
+		panic("reporter failed")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	WriteError(httptest.NewRecorder(), r, NewNotFoundError("missing"))
+	hook.Close()
+	hook.Close()
+	WriteError(httptest.NewRecorder(), r, NewNotFoundError("missing"))
+
+	if stats := hook.Stats(); calls != 1 || stats.Failed != 1 || stats.Delivered != 0 {
+		t.Errorf("Expected one failed delivery and none after Close, got %d calls %+v", calls, stats)
+	}
+}
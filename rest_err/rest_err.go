@@ -3,23 +3,45 @@ package rest_err
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 )
 
 type RestErr struct {
 	Message   string    `json:"message" example:"invalid request parameters"` // Human readable message
 	Err       string    `json:"error" example:"bad request"`
-	Code      int       `json:"code" example:"400"` // HTTP status code
-	Causes    []Causes  `json:"causes,omitempty"`   // Detailed error causes, most common for json field validation errors
-	Timestamp time.Time `json:"timestamp"`          // When the error occurred
-	Wrapped   error     `json:"-"`                  // Underlying error (not exposed in JSON)
+	Code      int       `json:"code" example:"400"`                          // HTTP status code
+	AppCode   string    `json:"app_code,omitempty" example:"USER_NOT_FOUND"` // Application specific error code
+	Causes    []Causes  `json:"causes,omitempty"`                            // Detailed error causes, most common for json field validation errors
+	Details   any       `json:"details,omitempty"`                           // Typed detail payload, see Detailed and DetailsAs
+	Meta      Meta      `json:"meta,omitempty"`                              // Extension metadata for programmatic use by clients
+	Key       string    `json:"-"`                                           // Translation key of the message, see Bundle
+	Template  string    `json:"-"`                                           // Message template before interpolation, see NewTemplateError
+	Params    Params    `json:"-"`                                           // Named parameters of the message
+	Timestamp time.Time `json:"timestamp"`                                   // When the error occurred
+	Wrapped   error     `json:"-"`                                           // Underlying error (not exposed in JSON)
+	format    string    // Message before formatting, groups errors in Fingerprint
+	stack     []uintptr // Program counters of where the error was created
+	creation  *creation // Creation of a 5xx error to report, shared by copies
 }
 
 type Causes struct {
-	Field   string `json:"field" example:"email"`                   // Field or parameter that caused the error
-	Message string `json:"message" example:"invalid email address"` // Description of the cause
-}
+	Field    string `json:"field" xml:"field" example:"email"`                           // Field or parameter that caused the error
+	Message  string `json:"message" xml:"message" example:"invalid email address"`       // Description of the cause
+	Location string `json:"location,omitempty" xml:"location,omitempty" example:"query"` // Where the field was read from, see the Location constants
+	Key      string `json:"-" xml:"-"`                                                   // Translation key of the message, see Bundle
+}
+
+// Locations a cause's field can be read from
+const (
+	LocationBody   = "body"
+	LocationQuery  = "query"
+	LocationPath   = "path"
+	LocationHeader = "header"
+)
 
 func (r *RestErr) Error() string {
 	if r.Wrapped != nil {
@@ -33,10 +55,46 @@ func (r *RestErr) Unwrap() error {
 	return r.Wrapped
 }
 
-// WithCause wraps an underlying error
+// Clone returns a copy of the error with its own Causes slice, Meta and Params maps
+func (r *RestErr) Clone() *RestErr {
+	clone := *r
+	clone.Causes = slices.Clone(r.Causes)
+	clone.Meta = maps.Clone(r.Meta)
+	clone.Params = maps.Clone(r.Params)
+	return &clone
+}
+
+// WithCause returns a copy of the error wrapping an underlying error, the receiver is left untouched
 func (r *RestErr) WithCause(err error) *RestErr {
-	r.Wrapped = err
-	return r
+	clone := r.Clone()
+	clone.Wrapped = err
+	return clone
+}
+
+// WithMessage returns a copy of the error with a new message
+func (r *RestErr) WithMessage(message string) *RestErr {
+	clone := r.Clone()
+	clone.Message = message
+	return clone
+}
+
+// WithAppCode returns a copy of the error with an application specific code
+func (r *RestErr) WithAppCode(code string) *RestErr {
+	clone := r.Clone()
+	clone.AppCode = code
+	return clone
+}
+
+// WithCauses returns a copy of the error with causes appended
+func (r *RestErr) WithCauses(causes ...Causes) *RestErr {
+	clone := r.Clone()
+	clone.Causes = append(clone.Causes, causes...)
+	return clone
+}
+
+// WithField returns a copy of the error with a cause for field appended
+func (r *RestErr) WithField(field, message string) *RestErr {
+	return r.WithCauses(Causes{Field: field, Message: message})
 }
 
 // IsClientError returns true if the error is a 4xx client error
@@ -65,16 +123,27 @@ func (r *RestErr) IsForbidden() bool {
 }
 
 func NewRestErr(message, err string, code int, causes []Causes) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   message,
+		format:    message,
 		Err:       err,
 		Code:      code,
 		Causes:    causes,
-		Timestamp: time.Now(),
+		Timestamp: now(),
+		stack:     callers(),
+	})
+}
+
+// errText returns the lowercase status text used in the Err field, e.g. "not found"
+func errText(code int) string {
+	if code == StatusClientClosedRequest {
+		return "client closed request"
 	}
+	return strings.ToLower(http.StatusText(code))
 }
 
 // NewRestErrFromError converts a standard Go error to a RestErr
+// Joined errors (errors.Join or fmt.Errorf with several %w) are combined with Aggregate
 // Defaults to 500 Internal Server Error
 func NewRestErrFromError(err error) *RestErr {
 	if err == nil {
@@ -82,19 +151,33 @@ func NewRestErrFromError(err error) *RestErr {
 	}
 
 	// Check if it's already a RestErr
+	if restErr, ok := err.(*RestErr); ok {
+		return restErr
+	}
+
+	// Joined errors are aggregated instead of picking the first RestErr found,
+	// a joined error wrapping no errors is converted as a plain error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		if aggregated := Aggregate(joined.Unwrap()...); aggregated != nil {
+			return aggregated
+		}
+	}
+
 	var restErr *RestErr
 	if errors.As(err, &restErr) {
 		return restErr
 	}
 
 	// Default to internal server error
-	return &RestErr{
+	return created(&RestErr{
 		Message:   "An unexpected error occurred",
+		format:    "An unexpected error occurred",
 		Err:       "internal server error",
 		Code:      http.StatusInternalServerError,
 		Wrapped:   err,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 // ParseError attempts to extract a RestErr from an error chain
@@ -110,184 +193,235 @@ func ParseError(err error) (*RestErr, bool) {
 }
 
 func NewBadRequestError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "bad request",
 		Code:      http.StatusBadRequest,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewBadRequestValidationError(message string, causes []Causes) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   message,
+		format:    message,
 		Err:       "bad request",
 		Code:      http.StatusBadRequest,
 		Causes:    causes,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewInternalServerError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "internal server error",
 		Code:      http.StatusInternalServerError,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewNotFoundError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "not found",
 		Code:      http.StatusNotFound,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewForbiddenError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "forbidden",
 		Code:      http.StatusForbidden,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewUnauthorizedError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "unauthorized",
 		Code:      http.StatusUnauthorized,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewBadGatewayError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "bad gateway",
 		Code:      http.StatusBadGateway,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewConflictError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "conflict",
 		Code:      http.StatusConflict,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewUnprocessableEntityError(message string, causes []Causes) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   message,
+		format:    message,
 		Err:       "unprocessable entity",
 		Code:      http.StatusUnprocessableEntity,
 		Causes:    causes,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewTooManyRequestsError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "too many requests",
 		Code:      http.StatusTooManyRequests,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewServiceUnavailableError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "service unavailable",
 		Code:      http.StatusServiceUnavailable,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewGatewayTimeoutError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "gateway timeout",
 		Code:      http.StatusGatewayTimeout,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewPreconditionFailedError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "precondition failed",
 		Code:      http.StatusPreconditionFailed,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewNotAcceptableError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "not acceptable",
 		Code:      http.StatusNotAcceptable,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewLengthRequiredError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "length required",
 		Code:      http.StatusLengthRequired,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewUnsupportedMediaTypeError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "unsupported media type",
 		Code:      http.StatusUnsupportedMediaType,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
+}
+
+func NewRequestEntityTooLargeError(message string, args ...any) *RestErr {
+	return created(&RestErr{
+		Message:   fmt.Sprintf(message, args...),
+		format:    message,
+		Err:       "request entity too large",
+		Code:      http.StatusRequestEntityTooLarge,
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewExpectationFailedError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "expectation failed",
 		Code:      http.StatusExpectationFailed,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewConflictValidationError(message string, causes []Causes) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   message,
+		format:    message,
 		Err:       "conflict",
 		Code:      http.StatusConflict,
 		Causes:    causes,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewRequestTimeoutError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "request timeout",
 		Code:      http.StatusRequestTimeout,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
 
 func NewHttpVersionNotSupportedError(message string, args ...any) *RestErr {
-	return &RestErr{
+	return created(&RestErr{
 		Message:   fmt.Sprintf(message, args...),
+		format:    message,
 		Err:       "http version not supported",
 		Code:      http.StatusHTTPVersionNotSupported,
-		Timestamp: time.Now(),
-	}
+		Timestamp: now(),
+		stack:     callers(),
+	})
 }
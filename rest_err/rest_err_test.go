@@ -244,6 +244,7 @@ func TestErrorConstructors(t *testing.T) {
 		{"NotAcceptable", func() *RestErr { return NewNotAcceptableError("not acceptable") }, http.StatusNotAcceptable, "not acceptable"},
 		{"LengthRequired", func() *RestErr { return NewLengthRequiredError("length required") }, http.StatusLengthRequired, "length required"},
 		{"UnsupportedMediaType", func() *RestErr { return NewUnsupportedMediaTypeError("unsupported") }, http.StatusUnsupportedMediaType, "unsupported media type"},
+		{"RequestEntityTooLarge", func() *RestErr { return NewRequestEntityTooLargeError("too large") }, http.StatusRequestEntityTooLarge, "request entity too large"},
 		{"ExpectationFailed", func() *RestErr { return NewExpectationFailedError("expectation") }, http.StatusExpectationFailed, "expectation failed"},
 		{"RequestTimeout", func() *RestErr { return NewRequestTimeoutError("timeout") }, http.StatusRequestTimeout, "request timeout"},
 		{"HTTPVersionNotSupported", func() *RestErr { return NewHttpVersionNotSupportedError("not supported") }, http.StatusHTTPVersionNotSupported, "http version not supported"},
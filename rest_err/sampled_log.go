@@ -0,0 +1,174 @@
+package rest_err
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SampledLogger logs errors through slog, grouped by fingerprint. The first occurrence of
+// an error is logged in full, with its stack. Later occurrences are sampled, at most one per
+// interval per fingerprint, and carry a "suppressed" count of the occurrences skipped since
+// the previous record. Flush, or Run in the background, logs "suppressed N" summaries for
+// errors that stopped occurring. A SampledLogger is safe for concurrent use
+type SampledLogger struct {
+	logger   *slog.Logger
+	interval time.Duration
+	clock    Clock
+
+	mu     sync.Mutex
+	groups map[string]*logGroup
+}
+
+type logGroup struct {
+	logged     time.Time // When the last record was logged
+	suppressed uint64
+	sample     *RestErr // Last suppressed occurrence
+}
+
+// SampledLoggerOption configures a SampledLogger
+type SampledLoggerOption func(*SampledLogger)
+
+// WithSamplingClock sets the clock intervals are measured with, time.Now by default.
+// It is independent of the package clock, so a fixed SetClock does not suppress every repeat
+func WithSamplingClock(c Clock) SampledLoggerOption {
+	return func(l *SampledLogger) {
+		if c != nil {
+			l.clock = c
+		}
+	}
+}
+
+// DefaultSamplingInterval is the interval used by NewSampledLogger when interval is not positive
+const DefaultSamplingInterval = time.Minute
+
+// NewSampledLogger returns a logger logging each error at most once per interval, slog.Default() is used when logger is nil
+func NewSampledLogger(logger *slog.Logger, interval time.Duration, opts ...SampledLoggerOption) *SampledLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if interval <= 0 {
+		interval = DefaultSamplingInterval
+	}
+	l := &SampledLogger{logger: logger, interval: interval, clock: ClockFunc(time.Now), groups: map[string]*logGroup{}}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Log logs restErr unless an error with the same fingerprint was logged less than an interval ago.
+// 5xx errors are logged at error level, others at warn level, and their creation is reported, see EventCreated
+func (l *SampledLogger) Log(ctx context.Context, restErr *RestErr) {
+	reportCreated(restErr)
+	fingerprint := restErr.Fingerprint()
+	now := l.clock.Now()
+
+	l.mu.Lock()
+	group, seen := l.groups[fingerprint]
+	if seen && now.Sub(group.logged) < l.interval {
+		group.suppressed++
+		group.sample = restErr
+		l.mu.Unlock()
+		return
+	}
+	if !seen {
+		group = &logGroup{}
+		l.groups[fingerprint] = group
+	}
+	suppressed := group.suppressed
+	group.logged, group.suppressed, group.sample = now, 0, nil
+	l.mu.Unlock()
+
+	attrs := errorAttrs(restErr, fingerprint)
+	if !seen {
+		attrs = append(attrs, slog.String("stack", formatStack(restErr)))
+	}
+	if suppressed > 0 {
+		attrs = append(attrs, slog.Uint64("suppressed", suppressed))
+	}
+	l.logger.LogAttrs(ctx, logLevel(restErr), restErr.Error(), attrs...)
+}
+
+// Flush logs a summary for each error with occurrences suppressed for at least an interval,
+// and forgets errors that did not occur during the last interval
+func (l *SampledLogger) Flush(ctx context.Context) {
+	now := l.clock.Now()
+
+	type summary struct {
+		fingerprint string
+		suppressed  uint64
+		sample      *RestErr
+	}
+	var summaries []summary
+
+	l.mu.Lock()
+	for fingerprint, group := range l.groups {
+		if now.Sub(group.logged) < l.interval {
+			continue
+		}
+		if group.suppressed == 0 {
+			delete(l.groups, fingerprint)
+			continue
+		}
+		summaries = append(summaries, summary{fingerprint, group.suppressed, group.sample})
+		group.logged, group.suppressed, group.sample = now, 0, nil
+	}
+	l.mu.Unlock()
+
+	for _, s := range summaries {
+		attrs := append(errorAttrs(s.sample, s.fingerprint), slog.Uint64("suppressed", s.suppressed))
+		l.logger.LogAttrs(ctx, logLevel(s.sample), fmt.Sprintf("suppressed %d occurrences: %s", s.suppressed, s.sample.Error()), attrs...)
+	}
+}
+
+// Run calls Flush every interval until ctx is done
+func (l *SampledLogger) Run(ctx context.Context) {
+	if l.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.Flush(ctx)
+		}
+	}
+}
+
+func errorAttrs(restErr *RestErr, fingerprint string) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.Int("status", restErr.Code),
+		slog.String("fingerprint", fingerprint),
+	}
+	if restErr.AppCode != "" {
+		attrs = append(attrs, slog.String("app_code", restErr.AppCode))
+	}
+	if len(restErr.Causes) > 0 {
+		attrs = append(attrs, slog.Any("causes", restErr.Causes))
+	}
+	return attrs
+}
+
+func logLevel(restErr *RestErr) slog.Level {
+	if restErr.IsServerError() {
+		return slog.LevelError
+	}
+	return slog.LevelWarn
+}
+
+// formatStack formats the stack of restErr one "function file:line" frame per line
+func formatStack(restErr *RestErr) string {
+	var b strings.Builder
+	for _, frame := range restErr.Stack() {
+		fmt.Fprintf(&b, "%s %s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+	return b.String()
+}
@@ -0,0 +1,180 @@
+package rest_err
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestLogger(t *testing.T) (*slog.Logger, func() []map[string]any) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&lockedWriter{mu: &mu, w: &buf}, nil))
+
+	return logger, func() []map[string]any {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var records []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var record map[string]any
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			records = append(records, record)
+		}
+		buf.Reset()
+		return records
+	}
+}
+
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  *bytes.Buffer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+func TestSampledLogger(t *testing.T) {
+	clock := fixedTime
+	logger, records := newTestLogger(t)
+	sampled := NewSampledLogger(logger, time.Second, WithSamplingClock(ClockFunc(func() time.Time { return clock })))
+	ctx := context.Background()
+	newErr := func(id int) *RestErr {
+		return NewBadGatewayError("upstream %d failed", id)
+	}
+
+	sampled.Log(ctx, newErr(1))
+	first := records()
+	if len(first) != 1 {
+		t.Fatalf("Expected the first occurrence to be logged, got %v", first)
+	}
+	if first[0]["level"] != "ERROR" || first[0]["msg"] != "upstream 1 failed" || first[0]["status"] != float64(502) {
+		t.Errorf("Unexpected record %v", first[0])
+	}
+	if stack, _ := first[0]["stack"].(string); !strings.Contains(stack, "TestSampledLogger") {
+		t.Errorf("Expected the stack on the first occurrence, got %v", first[0]["stack"])
+	}
+
+	for i := 2; i <= 5; i++ {
+		sampled.Log(ctx, newErr(i))
+	}
+	if suppressed := records(); len(suppressed) != 0 {
+		t.Errorf("Expected occurrences within the interval to be suppressed, got %v", suppressed)
+	}
+
+	clock = clock.Add(time.Second)
+	sampled.Log(ctx, newErr(6))
+	sample := records()
+	if len(sample) != 1 || sample[0]["suppressed"] != float64(4) || sample[0]["stack"] != nil {
+		t.Errorf("Expected a sampled record with the suppressed count, got %v", sample)
+	}
+
+	t.Run("other fingerprints are independent", func(t *testing.T) {
+		sampled.Log(ctx, NewNotFoundError("missing").WithAppCode("MISSING"))
+		logged := records()
+		if len(logged) != 1 || logged[0]["level"] != "WARN" || logged[0]["app_code"] != "MISSING" {
+			t.Errorf("Unexpected records %v", logged)
+		}
+	})
+}
+
+func TestSampledLogger_Flush(t *testing.T) {
+	clock := fixedTime
+	logger, records := newTestLogger(t)
+	sampled := NewSampledLogger(logger, time.Second, WithSamplingClock(ClockFunc(func() time.Time { return clock })))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		sampled.Log(ctx, NewServiceUnavailableError("unavailable"))
+	}
+	records()
+
+	sampled.Flush(ctx)
+	if early := records(); len(early) != 0 {
+		t.Errorf("Expected no summary before the interval, got %v", early)
+	}
+
+	clock = clock.Add(time.Second)
+	sampled.Flush(ctx)
+	summary := records()
+	if len(summary) != 1 || summary[0]["suppressed"] != float64(2) || summary[0]["msg"] != "suppressed 2 occurrences: unavailable" {
+		t.Errorf("Expected a summary record, got %v", summary)
+	}
+
+	clock = clock.Add(time.Second)
+	sampled.Flush(ctx)
+	if idle := records(); len(idle) != 0 {
+		t.Errorf("Expected no summary without suppressed occurrences, got %v", idle)
+	}
+	sampled.mu.Lock()
+	groups := len(sampled.groups)
+	sampled.mu.Unlock()
+	if groups != 0 {
+		t.Errorf("Expected idle fingerprints to be forgotten, got %d", groups)
+	}
+}
+
+func TestSampledLogger_PackageClock(t *testing.T) {
+	resetTimestamps(t)
+	SetClock(ClockFunc(func() time.Time { return fixedTime }))
+
+	logger, records := newTestLogger(t)
+	sampled := NewSampledLogger(logger, time.Millisecond)
+	sampled.Log(context.Background(), NewInternalServerError("boom"))
+	time.Sleep(2 * time.Millisecond)
+	sampled.Log(context.Background(), NewInternalServerError("boom"))
+
+	if logged := records(); len(logged) != 2 {
+		t.Errorf("Expected a fixed package clock not to suppress repeats, got %d records", len(logged))
+	}
+}
+
+func TestSampledLogger_Concurrent(t *testing.T) {
+	logger, records := newTestLogger(t)
+	sampled := NewSampledLogger(logger, time.Hour)
+	restErr := NewInternalServerError("boom")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				sampled.Log(context.Background(), restErr)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if logged := records(); len(logged) != 1 {
+		t.Errorf("Expected a single record, got %d", len(logged))
+	}
+}
+
+func TestSampledLogger_NonPositiveInterval(t *testing.T) {
+	logger, _ := newTestLogger(t)
+	sampled := NewSampledLogger(logger, 0)
+	if sampled.interval != DefaultSamplingInterval {
+		t.Errorf("Expected the default interval, got %v", sampled.interval)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sampled.Run(ctx)
+	(&SampledLogger{}).Run(ctx)
+}
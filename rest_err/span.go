@@ -0,0 +1,101 @@
+package rest_err
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Attribute keys recorded on spans
+const (
+	AttrHTTPStatusCode      = "http.response.status_code"
+	AttrAppCode             = "app.error_code"
+	AttrExceptionType       = "exception.type"
+	AttrExceptionMessage    = "exception.message"
+	AttrExceptionStacktrace = "exception.stacktrace"
+)
+
+// SpanStatus is the status of a span, numbered as OpenTelemetry status codes
+type SpanStatus int
+
+const (
+	SpanStatusUnset SpanStatus = iota
+	SpanStatusError
+	SpanStatusOK
+)
+
+// SpanAttribute is a span attribute, values are strings or ints
+type SpanAttribute struct {
+	Key   string
+	Value any
+}
+
+// Span is the part of a tracing span errors are recorded on. Adapt the span of your tracing
+// SDK to it, e.g. an OpenTelemetry trace.Span:
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetStatus(status rest_err.SpanStatus, description string) {
+//		s.Span.SetStatus(codes.Code(status), description)
+//	}
+//
+// with SetAttributes and AddEvent converting SpanAttribute to attribute.KeyValue
+type Span interface {
+	SetStatus(status SpanStatus, description string)
+	SetAttributes(attrs ...SpanAttribute)
+	AddEvent(name string, attrs ...SpanAttribute)
+}
+
+// RecordError records err on span, using the RestErr found in its chain:
+//   - the http.response.status_code attribute, and the app.error_code attribute when AppCode is set
+//   - an "exception" event with the error type, message and the stack of where the error was created.
+//     The type is the one of the innermost wrapped error, or of err when nothing is wrapped
+//   - an error status for 5xx errors, client errors leave the status untouched
+//
+// Errors without a RestErr are recorded as 500s without a stack; no RestErr is created for them,
+// so hooks do not receive EventCreated reports
+func RecordError(span Span, err error) {
+	if span == nil || err == nil {
+		return
+	}
+	restErr, ok := ParseError(err)
+	if !ok {
+		restErr = &RestErr{
+			Message: "An unexpected error occurred",
+			Err:     "internal server error",
+			Code:    http.StatusInternalServerError,
+			Wrapped: err,
+		}
+	}
+
+	attrs := []SpanAttribute{{Key: AttrHTTPStatusCode, Value: restErr.Code}}
+	if restErr.AppCode != "" {
+		attrs = append(attrs, SpanAttribute{Key: AttrAppCode, Value: restErr.AppCode})
+	}
+	span.SetAttributes(attrs...)
+
+	exceptionType := fmt.Sprintf("%T", err)
+	if types := wrappedTypes(restErr.Wrapped); len(types) > 0 {
+		exceptionType = types[len(types)-1]
+	}
+	event := []SpanAttribute{
+		{Key: AttrExceptionType, Value: exceptionType},
+		{Key: AttrExceptionMessage, Value: restErr.Error()},
+	}
+	if stack := formatStack(restErr); stack != "" {
+		event = append(event, SpanAttribute{Key: AttrExceptionStacktrace, Value: stack})
+	}
+	span.AddEvent("exception", event...)
+
+	if restErr.IsServerError() {
+		span.SetStatus(SpanStatusError, restErr.Message)
+	}
+}
+
+// WithSpans records written errors on the span returned by fn for the request context,
+// typically the active span of your tracing SDK. fn may return nil when there is no span
+func WithSpans(fn func(context.Context) Span) WriterOption {
+	return func(wr *Writer) {
+		wr.spans = fn
+	}
+}
@@ -0,0 +1,133 @@
+package rest_err
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type fakeSpan struct {
+	status      SpanStatus
+	description string
+	attrs       map[string]any
+	events      map[string]map[string]any
+}
+
+func newFakeSpan() *fakeSpan {
+	return &fakeSpan{attrs: map[string]any{}, events: map[string]map[string]any{}}
+}
+
+func (s *fakeSpan) SetStatus(status SpanStatus, description string) {
+	s.status, s.description = status, description
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...SpanAttribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *fakeSpan) AddEvent(name string, attrs ...SpanAttribute) {
+	event := map[string]any{}
+	for _, a := range attrs {
+		event[a.Key] = a.Value
+	}
+	s.events[name] = event
+}
+
+func TestRecordError(t *testing.T) {
+	t.Run("server error", func(t *testing.T) {
+		span := newFakeSpan()
+		RecordError(span, NewServiceUnavailableError("db down").WithAppCode("DB_DOWN").WithCause(fs.ErrNotExist))
+
+		if span.status != SpanStatusError || span.description != "db down" {
+			t.Errorf("Expected error status, got %d '%s'", span.status, span.description)
+		}
+		if span.attrs[AttrHTTPStatusCode] != http.StatusServiceUnavailable || span.attrs[AttrAppCode] != "DB_DOWN" {
+			t.Errorf("Unexpected attributes %v", span.attrs)
+		}
+
+		exception, ok := span.events["exception"]
+		if !ok {
+			t.Fatal("Expected an exception event")
+		}
+		if exception[AttrExceptionType] != "*errors.errorString" || exception[AttrExceptionMessage] != "db down: file does not exist" {
+			t.Errorf("Unexpected exception event %v", exception)
+		}
+		if stack, _ := exception[AttrExceptionStacktrace].(string); !strings.Contains(stack, "TestRecordError") {
+			t.Errorf("Expected the creation stack, got %v", exception[AttrExceptionStacktrace])
+		}
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		span := newFakeSpan()
+		RecordError(span, NewNotFoundError("missing"))
+
+		if span.status != SpanStatusUnset {
+			t.Errorf("Expected client errors to leave the status unset, got %d", span.status)
+		}
+		if _, ok := span.attrs[AttrAppCode]; ok {
+			t.Error("Expected no app code attribute")
+		}
+		if span.events["exception"][AttrExceptionType] != "*rest_err.RestErr" {
+			t.Errorf("Unexpected exception event %v", span.events["exception"])
+		}
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		span := newFakeSpan()
+		RecordError(span, errors.New("boom"))
+
+		if span.status != SpanStatusError || span.attrs[AttrHTTPStatusCode] != http.StatusInternalServerError {
+			t.Errorf("Expected a 500 error status, got %d %v", span.status, span.attrs)
+		}
+		if span.events["exception"][AttrExceptionType] != "*errors.errorString" {
+			t.Errorf("Unexpected exception event %v", span.events["exception"])
+		}
+	})
+
+	t.Run("plain error creates no error", func(t *testing.T) {
+		var created atomic.Int64
+		hook := AddHook(ReporterFunc(func(Report) { created.Add(1) }), WithEvents(EventCreated))
+		RecordError(newFakeSpan(), errors.New("boom"))
+		RecordError(nil, errors.New("boom"))
+		hook.Close()
+
+		if n := created.Load(); n != 0 {
+			t.Errorf("Expected no creation reports, got %d", n)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		span := newFakeSpan()
+		RecordError(span, nil)
+		RecordError(nil, NewNotFoundError("missing"))
+		if len(span.attrs) != 0 || len(span.events) != 0 {
+			t.Error("Expected nothing to be recorded")
+		}
+	})
+}
+
+func TestWriter_Spans(t *testing.T) {
+	type spanKey struct{}
+	wr := NewWriter(WithSpans(func(ctx context.Context) Span {
+		span, _ := ctx.Value(spanKey{}).(Span)
+		return span
+	}))
+
+	span := newFakeSpan()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), spanKey{}, Span(span)))
+	wr.Write(httptest.NewRecorder(), req, NewBadGatewayError("upstream failed"))
+
+	if span.status != SpanStatusError || span.attrs[AttrHTTPStatusCode] != http.StatusBadGateway {
+		t.Errorf("Expected the written error on the span, got %d %v", span.status, span.attrs)
+	}
+
+	wr.Write(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), NewBadGatewayError("no span"))
+}
@@ -0,0 +1,87 @@
+package rest_err
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// NewTemplateError creates an error whose message is a template with named parameters:
+//
+//	rest_err.NewTemplateError(http.StatusNotFound, "user {id} not found", rest_err.Params{"id": id})
+//
+// Unlike the printf style constructors, "%" in the template or the params is kept as is.
+// The template and params are retained on the error for translation and log grouping
+func NewTemplateError(code int, template string, params Params) *RestErr {
+	return created(&RestErr{
+		Message:   interpolate(template, params),
+		Err:       errText(code),
+		Code:      code,
+		Template:  template,
+		Params:    maps.Clone(params),
+		Timestamp: now(),
+		stack:     callers(),
+	})
+}
+
+// NewLiteralError creates an error with the message used as is, without any formatting
+func NewLiteralError(code int, message string) *RestErr {
+	return created(&RestErr{
+		Message:   message,
+		format:    message,
+		Err:       errText(code),
+		Code:      code,
+		Timestamp: now(),
+		stack:     callers(),
+	})
+}
+
+// WithTemplate returns a copy of the error with its message set from a template with named parameters
+func (r *RestErr) WithTemplate(template string, params Params) *RestErr {
+	clone := r.Clone()
+	clone.Message = interpolate(template, params)
+	clone.Template = template
+	clone.Params = maps.Clone(params)
+	return clone
+}
+
+// Template sets the message from a template with named parameters
+func (b Builder) Template(template string, params Params) Builder {
+	b.err.Message = interpolate(template, params)
+	b.err.Template = template
+	b.err.Params = maps.Clone(params)
+	return b
+}
+
+// interpolate replaces {name} placeholders with the matching params, formatted with fmt.Sprint.
+// Placeholders without a matching param are kept as is
+func interpolate(template string, params Params) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		value, ok := params[template[start+1:end]]
+		if !ok {
+			b.WriteString(template[:start+1])
+			template = template[start+1:]
+			continue
+		}
+		b.WriteString(template[:start])
+		fmt.Fprint(&b, value)
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package rest_err
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewTemplateError(t *testing.T) {
+	params := Params{"id": "100%", "attempt": 3}
+	err := NewTemplateError(http.StatusNotFound, "user {id} not found after {attempt} attempts", params)
+
+	if err.Message != "user 100% not found after 3 attempts" {
+		t.Errorf("Expected interpolated message, got '%s'", err.Message)
+	}
+	if err.Code != http.StatusNotFound || err.Err != "not found" {
+		t.Errorf("Unexpected status %d %s", err.Code, err.Err)
+	}
+	if err.Template != "user {id} not found after {attempt} attempts" || err.Params["attempt"] != 3 {
+		t.Errorf("Expected template and params to be retained, got '%s' %v", err.Template, err.Params)
+	}
+	if err.Timestamp.IsZero() {
+		t.Error("Expected timestamp to be set")
+	}
+
+	params["id"] = "changed"
+	if err.Params["id"] != "100%" {
+		t.Error("Expected params to be copied")
+	}
+}
+
+func TestNewLiteralError(t *testing.T) {
+	err := NewLiteralError(http.StatusBadRequest, "discount must be below 100% {id}")
+	if err.Message != "discount must be below 100% {id}" || err.Template != "" {
+		t.Errorf("Expected literal message, got '%s'", err.Message)
+	}
+	if err.Err != "bad request" {
+		t.Errorf("Expected 'bad request', got '%s'", err.Err)
+	}
+}
+
+func TestRestErr_WithTemplate(t *testing.T) {
+	sentinel := NewNotFoundError("not found")
+	err := sentinel.WithTemplate("order {id} not found", Params{"id": 7})
+
+	if sentinel.Message != "not found" || sentinel.Template != "" {
+		t.Error("Expected sentinel to be untouched")
+	}
+	if err.Message != "order 7 not found" || err.Template != "order {id} not found" {
+		t.Errorf("Unexpected error %+v", err)
+	}
+
+	built := Build(http.StatusConflict).Template("email {email} taken", Params{"email": "a@b.c"}).Err()
+	if built.Message != "email a@b.c taken" || built.Params["email"] != "a@b.c" {
+		t.Errorf("Unexpected built error %+v", built)
+	}
+}
+
+func TestTemplate_Localization(t *testing.T) {
+	b := NewBundle("en")
+	b.AddMessages("pt-BR", map[string]string{"user {id} not found": "usuário {id} não encontrado"})
+
+	localized, lang := b.Localize(NewTemplateError(http.StatusNotFound, "user {id} not found", Params{"id": 42}), []string{"pt-BR"})
+	if lang != "pt-BR" || localized.Message != "usuário 42 não encontrado" {
+		t.Errorf("Expected the template to be used as key, got '%s' (%s)", localized.Message, lang)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	params := Params{"id": 42, "name": "ana"}
+	tests := map[string]string{
+		"user {id} not found":    "user 42 not found",
+		"{name}/{id}":            "ana/42",
+		"100% {unknown} {id}":    "100% {unknown} 42",
+		"unclosed {id":           "unclosed {id",
+		"nested {{id}}":          "nested {42}",
+		"no placeholders at all": "no placeholders at all",
+	}
+	for template, expected := range tests {
+		if message := interpolate(template, params); message != expected {
+			t.Errorf("Expected '%s' for '%s', got '%s'", expected, template, message)
+		}
+	}
+}
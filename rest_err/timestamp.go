@@ -0,0 +1,171 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock provides the current time used to stamp new errors
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to Clock
+type ClockFunc func() time.Time
+
+// Now returns f()
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// TimestampFormat controls how timestamps are encoded in JSON
+type TimestampFormat int
+
+const (
+	// TimestampDefault uses the package format set with SetTimestampFormat
+	TimestampDefault TimestampFormat = iota
+	// TimestampRFC3339Nano encodes a string with nanoseconds, as encoding/json does for time.Time
+	TimestampRFC3339Nano
+	// TimestampRFC3339 encodes a string with second precision
+	TimestampRFC3339
+	// TimestampUnixMillis encodes a number of milliseconds since the Unix epoch
+	TimestampUnixMillis
+	// TimestampOmit leaves the timestamp out of encoded documents
+	TimestampOmit
+)
+
+type timestampSettings struct {
+	clock  Clock
+	format TimestampFormat
+	utc    bool
+	omit   bool
+}
+
+var (
+	timestampMu sync.Mutex
+	timestamps  atomic.Pointer[timestampSettings]
+)
+
+func init() {
+	timestamps.Store(&timestampSettings{clock: ClockFunc(time.Now), format: TimestampRFC3339Nano, utc: true})
+}
+
+func updateTimestamps(fn func(*timestampSettings)) {
+	timestampMu.Lock()
+	defer timestampMu.Unlock()
+	settings := *timestamps.Load()
+	fn(&settings)
+	timestamps.Store(&settings)
+}
+
+// SetClock replaces the package clock used by every constructor, nil restores time.Now
+func SetClock(c Clock) {
+	if c == nil {
+		c = ClockFunc(time.Now)
+	}
+	updateTimestamps(func(s *timestampSettings) { s.clock = c })
+}
+
+// SetUTC controls whether new timestamps are normalized to UTC, enabled by default
+func SetUTC(enabled bool) {
+	updateTimestamps(func(s *timestampSettings) { s.utc = enabled })
+}
+
+// SetTimestampsEnabled controls whether new errors are stamped at all, enabled by default.
+// Errors created while disabled have a zero Timestamp, which is left out of encoded documents
+func SetTimestampsEnabled(enabled bool) {
+	updateTimestamps(func(s *timestampSettings) { s.omit = !enabled })
+}
+
+// SetTimestampFormat sets the package JSON timestamp format, TimestampRFC3339Nano by default
+func SetTimestampFormat(f TimestampFormat) {
+	if f == TimestampDefault {
+		f = TimestampRFC3339Nano
+	}
+	updateTimestamps(func(s *timestampSettings) { s.format = f })
+}
+
+// now returns the timestamp for a new error using the package settings
+func now() time.Time {
+	return timestamps.Load().now(nil)
+}
+
+func (s *timestampSettings) now(c Clock) time.Time {
+	if s.omit {
+		return time.Time{}
+	}
+	if c == nil {
+		c = s.clock
+	}
+	t := c.Now()
+	if s.utc {
+		t = t.UTC()
+	}
+	return t
+}
+
+// Factory creates errors stamped by its own Clock instead of the package clock,
+// e.g. a fixed clock for golden-file tests. UTC normalization and omission follow the package settings
+type Factory struct {
+	Clock Clock // nil uses the package clock
+}
+
+// Now returns the timestamp the factory stamps new errors with
+func (f *Factory) Now() time.Time {
+	return timestamps.Load().now(f.Clock)
+}
+
+// New returns an error with the given status, Err is derived from the status text
+func (f *Factory) New(code int, message string, causes []Causes) *RestErr {
+	return created(&RestErr{
+		Message:   message,
+		format:    message,
+		Err:       errText(code),
+		Code:      code,
+		Causes:    causes,
+		Timestamp: f.Now(),
+		stack:     callers(),
+	})
+}
+
+// encodeTimestamp returns the JSON value of t, or nil when it should be omitted
+func encodeTimestamp(t time.Time, f TimestampFormat) any {
+	if f == TimestampDefault {
+		f = timestamps.Load().format
+	}
+	if t.IsZero() || f == TimestampOmit {
+		return nil
+	}
+
+	switch f {
+	case TimestampRFC3339:
+		return t.Format(time.RFC3339)
+	case TimestampUnixMillis:
+		return t.UnixMilli()
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// decodeTimestamp accepts every format produced by encodeTimestamp
+func decodeTimestamp(raw json.RawMessage) (time.Time, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return time.Time{}, nil
+	}
+
+	var millis int64
+	if err := json.Unmarshal(raw, &millis); err == nil {
+		return time.UnixMilli(millis).UTC(), nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return time.Time{}, fmt.Errorf("timestamp must be a string or a number: %w", err)
+	}
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
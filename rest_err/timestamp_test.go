@@ -0,0 +1,137 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resetTimestamps restores the package timestamp settings after a test changes them
+func resetTimestamps(t *testing.T) {
+	t.Cleanup(func() {
+		SetClock(nil)
+		SetUTC(true)
+		SetTimestampsEnabled(true)
+		SetTimestampFormat(TimestampDefault)
+	})
+}
+
+var fixedTime = time.Date(2024, 1, 2, 3, 4, 5, 600_000_000, time.FixedZone("BRT", -3*60*60))
+
+func TestSetClock(t *testing.T) {
+	resetTimestamps(t)
+	SetClock(ClockFunc(func() time.Time { return fixedTime }))
+
+	err := NewNotFoundError("missing")
+	if !err.Timestamp.Equal(fixedTime) {
+		t.Errorf("Expected timestamp %v, got %v", fixedTime, err.Timestamp)
+	}
+	if err.Timestamp.Location() != time.UTC {
+		t.Errorf("Expected UTC timestamp, got %v", err.Timestamp.Location())
+	}
+
+	SetUTC(false)
+	if loc := NewNotFoundError("missing").Timestamp.Location(); loc.String() != "BRT" {
+		t.Errorf("Expected clock zone to be kept, got %v", loc)
+	}
+
+	SetClock(nil)
+	if time.Since(NewNotFoundError("missing").Timestamp) > time.Second {
+		t.Error("Expected SetClock(nil) to restore time.Now")
+	}
+}
+
+func TestSetTimestampsEnabled(t *testing.T) {
+	resetTimestamps(t)
+	SetTimestampsEnabled(false)
+
+	err := NewBadRequestError("invalid")
+	if !err.Timestamp.IsZero() {
+		t.Errorf("Expected zero timestamp, got %v", err.Timestamp)
+	}
+
+	data, _ := json.Marshal(err)
+	if strings.Contains(string(data), "timestamp") {
+		t.Errorf("Expected timestamp to be left out, got %s", data)
+	}
+}
+
+func TestSetTimestampFormat(t *testing.T) {
+	resetTimestamps(t)
+	SetClock(ClockFunc(func() time.Time { return fixedTime }))
+
+	tests := []struct {
+		format   TimestampFormat
+		expected string
+	}{
+		{TimestampRFC3339Nano, `"timestamp":"2024-01-02T06:04:05.6Z"`},
+		{TimestampRFC3339, `"timestamp":"2024-01-02T06:04:05Z"`},
+		{TimestampUnixMillis, `"timestamp":1704175445600`},
+	}
+
+	for _, tt := range tests {
+		SetTimestampFormat(tt.format)
+		restErr := NewConflictError("taken")
+
+		data, err := json.Marshal(restErr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(string(data), tt.expected) {
+			t.Errorf("Expected %s in %s", tt.expected, data)
+		}
+
+		var decoded RestErr
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := restErr.Timestamp
+		if tt.format == TimestampRFC3339 {
+			expected = expected.Truncate(time.Second)
+		}
+		if !decoded.Timestamp.Equal(expected) {
+			t.Errorf("Expected decoded timestamp %v, got %v", expected, decoded.Timestamp)
+		}
+
+		problem, _ := json.Marshal(restErr.ToProblem())
+		if !strings.Contains(string(problem), tt.expected) {
+			t.Errorf("Expected problem to use the same format, got %s", problem)
+		}
+	}
+
+	SetTimestampFormat(TimestampOmit)
+	if data, _ := json.Marshal(NewConflictError("taken")); strings.Contains(string(data), "timestamp") {
+		t.Errorf("Expected timestamp to be omitted, got %s", data)
+	}
+}
+
+func TestFactory(t *testing.T) {
+	resetTimestamps(t)
+
+	f := &Factory{Clock: ClockFunc(func() time.Time { return fixedTime })}
+	err := f.New(404, "user not found", nil)
+
+	if err.Code != 404 || err.Err != "not found" || err.Message != "user not found" {
+		t.Errorf("Unexpected error %+v", err)
+	}
+	if !err.Timestamp.Equal(fixedTime) || err.Timestamp.Location() != time.UTC {
+		t.Errorf("Expected factory clock in UTC, got %v", err.Timestamp)
+	}
+
+	if time.Since(NewNotFoundError("missing").Timestamp) > time.Second {
+		t.Error("Expected package clock to be unaffected by the factory")
+	}
+	if time.Since((&Factory{}).Now()) > time.Second {
+		t.Error("Expected a factory without clock to use the package clock")
+	}
+}
+
+func TestDecodeTimestamp(t *testing.T) {
+	if _, err := decodeTimestamp(json.RawMessage(`true`)); err == nil {
+		t.Error("Expected error for a boolean timestamp")
+	}
+	if ts, err := decodeTimestamp(json.RawMessage(`null`)); err != nil || !ts.IsZero() {
+		t.Errorf("Expected zero time for null, got %v, %v", ts, err)
+	}
+}
@@ -0,0 +1,337 @@
+package rest_err
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// RuleFunc checks a single field value against a rule.
+// param holds the text after '=' in the tag (e.g. "3" for min=3), empty when absent.
+// The returned error message becomes the Causes message. value may be a field promoted from
+// an unexported embedded struct, read it with reflect methods rather than value.Interface()
+type RuleFunc func(value reflect.Value, param string) error
+
+// Validator validates structs using `validate` struct tags such as `validate:"required,email,min=3"`
+type Validator struct {
+	mu    sync.RWMutex
+	rules map[string]RuleFunc
+}
+
+var defaultValidator = NewValidator()
+
+// NewValidator returns a Validator with the built-in rules registered:
+// required, omitempty, email, url, uuid, min, max, len and oneof
+func NewValidator() *Validator {
+	return &Validator{
+		rules: map[string]RuleFunc{
+			"required": ruleRequired,
+			"email":    ruleEmail,
+			"url":      ruleURL,
+			"uuid":     ruleUUID,
+			"min":      ruleMin,
+			"max":      ruleMax,
+			"len":      ruleLen,
+			"oneof":    ruleOneOf,
+		},
+	}
+}
+
+// RegisterRule adds or replaces a rule on the default Validator
+func RegisterRule(name string, fn RuleFunc) {
+	defaultValidator.RegisterRule(name, fn)
+}
+
+// Validate validates v with the default Validator
+func Validate(v any) *RestErr {
+	return defaultValidator.Validate(v)
+}
+
+// RegisterRule adds or replaces a rule, it is safe to call concurrently with Validate
+func (v *Validator) RegisterRule(name string, fn RuleFunc) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = fn
+}
+
+// Validate walks v (a struct or pointer to struct), including nested structs, slices and maps,
+// and returns a 400 RestErr with one Causes entry per violation, or nil when v is valid.
+// Fields are reported by their JSON names, e.g. "address.street" or "items[0].sku".
+// Unknown rule names are programming errors and cause a panic
+func (v *Validator) Validate(s any) *RestErr {
+	var causes []Causes
+	v.walk(reflect.ValueOf(s), "", &causes, map[visit]bool{})
+	if len(causes) == 0 {
+		return nil
+	}
+	restErr := NewBadRequestValidationError("validation failed", causes)
+	restErr.format = fmt.Sprintf("validation failed: %T", s) // Tells apart the fingerprints of validated types
+	return restErr
+}
+
+// visit is a pointer being walked, to stop on cyclic values
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// walk validates the values nested in val. Values are read through reflect.Value only,
+// so fields promoted from unexported embedded structs can be validated
+func (v *Validator) walk(val reflect.Value, path string, causes *[]Causes, walking map[visit]bool) {
+	for val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		if val.Kind() == reflect.Pointer {
+			key := visit{val.Pointer(), val.Type()}
+			if walking[key] {
+				return
+			}
+			walking[key] = true
+			defer delete(walking, key)
+		}
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		v.walkStruct(val, path, causes, walking)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			v.walk(val.Index(i), fmt.Sprintf("%s[%d]", path, i), causes, walking)
+		}
+	case reflect.Map:
+		keys := val.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+		})
+		for _, key := range keys {
+			v.walk(val.MapIndex(key), fmt.Sprintf("%s[%v]", path, key), causes, walking)
+		}
+	}
+}
+
+func (v *Validator) walkStruct(val reflect.Value, path string, causes *[]Causes, walking map[visit]bool) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() && !isEmbeddedStruct(field) {
+			continue
+		}
+
+		name, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+
+		fieldVal := val.Field(i)
+		if field.Anonymous && name == "" {
+			v.validateField(fieldVal, field.Tag.Get("validate"), path, causes)
+			v.walk(fieldVal, path, causes, walking)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		if !v.validateField(fieldVal, field.Tag.Get("validate"), fieldPath, causes) {
+			continue
+		}
+		v.walk(fieldVal, fieldPath, causes, walking)
+	}
+}
+
+// isEmbeddedStruct reports whether field embeds a struct or a pointer to a struct, whose fields
+// encoding/json promotes even when the embedded type is unexported
+func isEmbeddedStruct(field reflect.StructField) bool {
+	typ := field.Type
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return field.Anonymous && typ.Kind() == reflect.Struct
+}
+
+// validateField applies the rules in tag to val and reports whether nested values should be walked
+func (v *Validator) validateField(val reflect.Value, tag, path string, causes *[]Causes) bool {
+	if tag == "" || tag == "-" {
+		return tag == ""
+	}
+
+	rules := strings.Split(tag, ",")
+	if slices.Contains(rules, "omitempty") && val.IsZero() {
+		return false
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if name == "" || name == "omitempty" {
+			continue
+		}
+		fn, ok := v.rules[name]
+		if !ok {
+			panic(fmt.Sprintf("rest_err: unknown validation rule %q", name))
+		}
+		if err := fn(val, param); err != nil {
+			*causes = append(*causes, Causes{Field: path, Message: err.Error()})
+			if name == "required" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// jsonFieldName returns the JSON name of field, an empty name for untagged fields and false for fields skipped by encoding/json
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, true
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return value
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func ruleRequired(value reflect.Value, _ string) error {
+	if !value.IsValid() || value.IsZero() {
+		return errors.New("is required")
+	}
+	value = indirect(value)
+	if (value.Kind() == reflect.Slice || value.Kind() == reflect.Map) && value.Len() == 0 {
+		return errors.New("is required")
+	}
+	return nil
+}
+
+func ruleEmail(value reflect.Value, _ string) error {
+	s, ok := stringValue(value)
+	if !ok {
+		return nil
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return errors.New("must be a valid email address")
+	}
+	return nil
+}
+
+func ruleURL(value reflect.Value, _ string) error {
+	s, ok := stringValue(value)
+	if !ok {
+		return nil
+	}
+	u, err := url.ParseRequestURI(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.New("must be a valid URL")
+	}
+	return nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func ruleUUID(value reflect.Value, _ string) error {
+	s, ok := stringValue(value)
+	if !ok {
+		return nil
+	}
+	if !uuidPattern.MatchString(s) {
+		return errors.New("must be a valid UUID")
+	}
+	return nil
+}
+
+func ruleMin(value reflect.Value, param string) error {
+	return compareBound(value, param, "min", func(n, bound float64) bool { return n >= bound }, "at least")
+}
+
+func ruleMax(value reflect.Value, param string) error {
+	return compareBound(value, param, "max", func(n, bound float64) bool { return n <= bound }, "at most")
+}
+
+func ruleLen(value reflect.Value, param string) error {
+	return compareBound(value, param, "len", func(n, bound float64) bool { return n == bound }, "exactly")
+}
+
+func ruleOneOf(value reflect.Value, param string) error {
+	value = indirect(value)
+	if value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		return nil
+	}
+	allowed := strings.Fields(param)
+	if slices.Contains(allowed, fmt.Sprint(value)) {
+		return nil
+	}
+	return fmt.Errorf("must be one of [%s]", strings.Join(allowed, ", "))
+}
+
+// compareBound checks numbers by value and strings, slices and maps by length
+func compareBound(value reflect.Value, param, rule string, ok func(n, bound float64) bool, verb string) error {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic(fmt.Sprintf("rest_err: invalid parameter %q for validation rule %q", param, rule))
+	}
+
+	value = indirect(value)
+	var (
+		n    float64
+		unit string
+	)
+	switch value.Kind() {
+	case reflect.String:
+		n, unit = float64(utf8.RuneCountInString(value.String())), "characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n, unit = float64(value.Len()), "items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = value.Float()
+	default:
+		return nil
+	}
+
+	if ok(n, bound) {
+		return nil
+	}
+	switch unit {
+	case "items":
+		return fmt.Errorf("must contain %s %s items", verb, param)
+	case "characters":
+		return fmt.Errorf("must be %s %s characters long", verb, param)
+	}
+	return fmt.Errorf("must be %s %s", verb, param)
+}
+
+func stringValue(value reflect.Value) (string, bool) {
+	value = indirect(value)
+	if value.Kind() != reflect.String || value.Len() == 0 {
+		return "", false
+	}
+	return value.String(), true
+}
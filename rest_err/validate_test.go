@@ -0,0 +1,192 @@
+package rest_err
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type validateAddress struct {
+	Street string `json:"street" validate:"required"`
+	Zip    string `json:"zip_code" validate:"len=5"`
+}
+
+type validateItem struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1,max=10"`
+}
+
+type validateRequest struct {
+	Name     string                     `json:"name" validate:"required,min=3"`
+	Email    string                     `json:"email" validate:"required,email"`
+	Website  string                     `json:"website,omitempty" validate:"omitempty,url"`
+	Role     string                     `json:"role" validate:"oneof=admin user"`
+	Address  *validateAddress           `json:"address" validate:"required"`
+	Items    []validateItem             `json:"items" validate:"min=1"`
+	Labels   map[string]validateAddress `json:"labels"`
+	Ignored  string                     `json:"-" validate:"required"`
+	internal string
+}
+
+func validRequest() validateRequest {
+	return validateRequest{
+		Name:    "john",
+		Email:   "john@example.com",
+		Role:    "admin",
+		Address: &validateAddress{Street: "Main St", Zip: "12345"},
+		Items:   []validateItem{{SKU: "abc", Quantity: 1}},
+	}
+}
+
+func causeFields(err *RestErr) []string {
+	var fields []string
+	for _, c := range err.Causes {
+		fields = append(fields, c.Field)
+	}
+	return fields
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("valid struct", func(t *testing.T) {
+		req := validRequest()
+		if err := Validate(&req); err != nil {
+			t.Errorf("Expected no error, got %v (causes %+v)", err, err.Causes)
+		}
+	})
+
+	t.Run("top level violations", func(t *testing.T) {
+		req := validRequest()
+		req.Name = "jo"
+		req.Email = "not-an-email"
+		req.Website = "nope"
+		req.Role = "root"
+
+		err := Validate(req)
+		if err == nil {
+			t.Fatal("Expected validation error")
+		}
+		if err.Code != http.StatusBadRequest {
+			t.Errorf("Expected code 400, got %d", err.Code)
+		}
+		expected := []string{"name", "email", "website", "role"}
+		if !reflect.DeepEqual(causeFields(err), expected) {
+			t.Errorf("Expected fields %v, got %v", expected, causeFields(err))
+		}
+		if err.Causes[0].Message != "must be at least 3 characters long" {
+			t.Errorf("Unexpected min message '%s'", err.Causes[0].Message)
+		}
+	})
+
+	t.Run("required pointer", func(t *testing.T) {
+		req := validRequest()
+		req.Address = nil
+
+		err := Validate(req)
+		if err == nil || len(err.Causes) != 1 {
+			t.Fatalf("Expected one cause, got %v", err)
+		}
+		if err.Causes[0].Field != "address" || err.Causes[0].Message != "is required" {
+			t.Errorf("Unexpected cause %+v", err.Causes[0])
+		}
+	})
+
+	t.Run("nested structs slices and maps", func(t *testing.T) {
+		req := validRequest()
+		req.Address.Zip = "123"
+		req.Items = append(req.Items, validateItem{Quantity: 20})
+		req.Labels = map[string]validateAddress{"home": {Zip: "12345"}}
+
+		err := Validate(req)
+		if err == nil {
+			t.Fatal("Expected validation error")
+		}
+		expected := []string{"address.zip_code", "items[1].sku", "items[1].quantity", "labels[home].street"}
+		if !reflect.DeepEqual(causeFields(err), expected) {
+			t.Errorf("Expected fields %v, got %v", expected, causeFields(err))
+		}
+	})
+
+	t.Run("collection length", func(t *testing.T) {
+		req := validRequest()
+		req.Items = nil
+
+		err := Validate(req)
+		if err == nil || len(err.Causes) != 1 {
+			t.Fatalf("Expected one cause, got %v", err)
+		}
+		if err.Causes[0].Message != "must contain at least 1 items" {
+			t.Errorf("Unexpected message '%s'", err.Causes[0].Message)
+		}
+	})
+
+	t.Run("unexported embedded struct", func(t *testing.T) {
+		type base struct {
+			ID   string `json:"id" validate:"required"`
+			Kind string `json:"kind" validate:"oneof=a b"`
+		}
+		type request struct {
+			base
+			*validateAddress
+			Name string `json:"name" validate:"required"`
+		}
+
+		err := Validate(request{base: base{Kind: "c"}, validateAddress: &validateAddress{}})
+		expected := []string{"id", "kind", "street", "zip_code", "name"}
+		if err == nil || !reflect.DeepEqual(causeFields(err), expected) {
+			t.Errorf("Expected causes for %v, got %v", expected, err)
+		}
+	})
+
+	t.Run("cyclic pointers", func(t *testing.T) {
+		type node struct {
+			Name string `json:"name" validate:"required"`
+			Next *node  `json:"next"`
+		}
+		loop := &node{}
+		loop.Next = loop
+
+		err := Validate(loop)
+		if err == nil || !reflect.DeepEqual(causeFields(err), []string{"name"}) {
+			t.Errorf("Expected a single cause, got %v", err)
+		}
+	})
+
+	t.Run("unknown rule panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic for unknown rule")
+			}
+		}()
+		Validate(struct {
+			Name string `validate:"nonexistent"`
+		}{})
+	})
+}
+
+func TestValidator_RegisterRule(t *testing.T) {
+	v := NewValidator()
+	v.RegisterRule("lowercase", func(value reflect.Value, _ string) error {
+		if value.String() != strings.ToLower(value.String()) {
+			return errors.New("must be lowercase")
+		}
+		return nil
+	})
+
+	type request struct {
+		Username string `json:"username" validate:"required,lowercase"`
+	}
+
+	if err := v.Validate(request{Username: "john"}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	err := v.Validate(request{Username: "John"})
+	if err == nil || len(err.Causes) != 1 {
+		t.Fatalf("Expected one cause, got %v", err)
+	}
+	if err.Causes[0].Field != "username" || err.Causes[0].Message != "must be lowercase" {
+		t.Errorf("Unexpected cause %+v", err.Causes[0])
+	}
+}
@@ -0,0 +1,178 @@
+package rest_err
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Writer writes RestErr responses, rendering them in the media type negotiated
+// from the request's Accept header
+type Writer struct {
+	mediaTypes []string
+	html       *HTMLRenderer
+	json       *Encoder
+	bundle     *Bundle
+	metrics    *Metrics
+	spans      func(context.Context) Span
+}
+
+// WriterOption configures a Writer
+type WriterOption func(*Writer)
+
+// WithMediaTypes restricts the media types offered during negotiation, in order of server preference.
+// Unsupported types are ignored, the first offered type is used when the request has no Accept header
+func WithMediaTypes(types ...string) WriterOption {
+	return func(wr *Writer) {
+		var offered []string
+		for _, t := range types {
+			if isSupportedMediaType(t) {
+				offered = append(offered, t)
+			}
+		}
+		if len(offered) > 0 {
+			wr.mediaTypes = offered
+		}
+	}
+}
+
+// WithHTMLRenderer sets the renderer used for text/html responses
+func WithHTMLRenderer(h *HTMLRenderer) WriterOption {
+	return func(wr *Writer) {
+		wr.html = h
+	}
+}
+
+// WithJSONEncoder sets the encoder shaping application/json responses, encoding/json is used by default
+func WithJSONEncoder(enc *Encoder) WriterOption {
+	return func(wr *Writer) {
+		wr.json = enc
+	}
+}
+
+// WithBundle localizes errors with the bundle, negotiating the language from the
+// request's Accept-Language header. Content-Language is set when a message was translated
+func WithBundle(b *Bundle) WriterOption {
+	return func(wr *Writer) {
+		wr.bundle = b
+	}
+}
+
+// NewWriter returns a Writer offering JSON, problem+json, XML, problem+xml, plain text and HTML
+func NewWriter(opts ...WriterOption) *Writer {
+	wr := &Writer{
+		mediaTypes: []string{MediaTypeJSON, MediaTypeProblemJSON, MediaTypeXML, MediaTypeProblemXML, MediaTypeText, MediaTypeHTML},
+		html:       NewHTMLRenderer(),
+	}
+	for _, opt := range opts {
+		opt(wr)
+	}
+	return wr
+}
+
+// DefaultWriter is the Writer used by WriteError
+var DefaultWriter = NewWriter()
+
+// WriteError writes err to w using DefaultWriter
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultWriter.Write(w, r, err)
+}
+
+// Write converts err with NewRestErrFromError and writes it with its status code.
+// The body is rendered in the media type preferred by the Accept header, falling back to JSON,
+// or to the first offered type when JSON is not offered. When the Accept header refuses every
+// offered type with q=0 a 406 Not Acceptable is written as JSON instead. A nil err writes nothing. Written errors are reported to the registered hooks
+func (wr *Writer) Write(w http.ResponseWriter, r *http.Request, err error) {
+	restErr := NewRestErrFromError(err)
+	if restErr == nil {
+		return
+	}
+
+	mediaType, ok := negotiateMediaType(r.Header.Get("Accept"), wr.mediaTypes)
+	if !ok {
+		restErr = NewNotAcceptableError("none of the requested media types are supported, use one of: %s", strings.Join(wr.mediaTypes, ", "))
+		mediaType = MediaTypeJSON
+	}
+
+	reported := restErr
+	h := w.Header()
+	h.Add("Vary", "Accept")
+	if wr.bundle != nil {
+		var lang string
+		restErr, lang = wr.bundle.Localize(restErr, wr.bundle.Match(r.Header.Get("Accept-Language")))
+		if lang != "" {
+			h.Set("Content-Language", lang)
+		}
+		h.Add("Vary", "Accept-Language")
+	}
+	h.Set("X-Content-Type-Options", "nosniff")
+	if strings.HasPrefix(mediaType, "text/") {
+		h.Set("Content-Type", mediaType+"; charset=utf-8")
+	} else {
+		h.Set("Content-Type", mediaType)
+	}
+	w.WriteHeader(restErr.Code)
+
+	_ = wr.render(w, r, mediaType, restErr)
+	if wr.metrics != nil {
+		wr.metrics.Observe(reported, r)
+	}
+	if wr.spans != nil {
+		RecordError(wr.spans(r.Context()), reported)
+	}
+	reportCreated(reported)
+	report(EventWritten, reported, r)
+}
+
+func (wr *Writer) render(w io.Writer, r *http.Request, mediaType string, restErr *RestErr) error {
+	switch mediaType {
+	case MediaTypeProblemJSON:
+		problem := restErr.ToProblem()
+		problem.Instance = r.URL.RequestURI()
+		return json.NewEncoder(w).Encode(problem)
+	case MediaTypeXML:
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		return xml.NewEncoder(w).Encode(restErr)
+	case MediaTypeProblemXML:
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		problem := restErr.ToProblem()
+		problem.Instance = r.URL.RequestURI()
+		return xml.NewEncoder(w).Encode(problem)
+	case MediaTypeText:
+		return renderText(w, restErr)
+	case MediaTypeHTML:
+		return wr.html.Render(w, restErr)
+	}
+
+	if wr.json == nil {
+		return json.NewEncoder(w).Encode(restErr)
+	}
+	data, err := wr.json.Marshal(restErr)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func renderText(w io.Writer, restErr *RestErr) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s: %s\n", restErr.Code, http.StatusText(restErr.Code), restErr.Message)
+	for _, c := range restErr.Causes {
+		if c.Field != "" {
+			fmt.Fprintf(&b, "- %s: %s\n", c.Field, c.Message)
+		} else {
+			fmt.Fprintf(&b, "- %s\n", c.Message)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,174 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func writeWith(wr *Writer, accept string, err error) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/users/42?expand=true", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	wr.Write(rec, req, err)
+	return rec
+}
+
+func TestWriter_Write(t *testing.T) {
+	restErr := NewBadRequestValidationError("invalid <input>", []Causes{{Field: "email", Message: "is required"}})
+	wr := NewWriter()
+
+	t.Run("defaults to json", func(t *testing.T) {
+		rec := writeWith(wr, "", restErr)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != MediaTypeJSON {
+			t.Errorf("Expected JSON content type, got '%s'", ct)
+		}
+		if vary := rec.Header().Get("Vary"); vary != "Accept" {
+			t.Errorf("Expected Vary: Accept, got '%s'", vary)
+		}
+
+		var decoded RestErr
+		if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded.Message != restErr.Message || decoded.Causes[0].Field != "email" {
+			t.Errorf("Unexpected body %+v", decoded)
+		}
+	})
+
+	t.Run("problem json", func(t *testing.T) {
+		rec := writeWith(wr, MediaTypeProblemJSON, restErr)
+		if ct := rec.Header().Get("Content-Type"); ct != MediaTypeProblemJSON {
+			t.Errorf("Expected problem+json content type, got '%s'", ct)
+		}
+
+		var problem Problem
+		if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if problem.Title != "Bad Request" || problem.Status != 400 || problem.Detail != restErr.Message {
+			t.Errorf("Unexpected problem %+v", problem)
+		}
+		if problem.Instance != "/users/42?expand=true" {
+			t.Errorf("Expected instance to be the request URI, got '%s'", problem.Instance)
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		rec := writeWith(wr, MediaTypeXML, restErr)
+		if ct := rec.Header().Get("Content-Type"); ct != MediaTypeXML {
+			t.Errorf("Expected XML content type, got '%s'", ct)
+		}
+		body := rec.Body.String()
+		if !strings.HasPrefix(body, "<?xml") || !strings.Contains(body, "<error><message>invalid &lt;input&gt;</message>") {
+			t.Errorf("Unexpected XML body %s", body)
+		}
+
+		var decoded RestErr
+		if err := xml.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded.Code != 400 || len(decoded.Causes) != 1 {
+			t.Errorf("Unexpected decoded XML %+v", decoded)
+		}
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		rec := writeWith(wr, "text/plain", restErr)
+		if ct := rec.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+			t.Errorf("Expected text content type, got '%s'", ct)
+		}
+		expected := "400 Bad Request: invalid <input>\n- email: is required\n"
+		if rec.Body.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, rec.Body.String())
+		}
+	})
+
+	t.Run("html is escaped", func(t *testing.T) {
+		rec := writeWith(wr, "text/html", restErr)
+		if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+			t.Errorf("Expected HTML content type, got '%s'", ct)
+		}
+		body := rec.Body.String()
+		if !strings.Contains(body, "invalid &lt;input&gt;") || strings.Contains(body, "<input>") {
+			t.Errorf("Expected escaped message, got %s", body)
+		}
+		if !strings.Contains(body, "<strong>email</strong>: is required") {
+			t.Errorf("Expected causes to be rendered, got %s", body)
+		}
+	})
+
+	t.Run("unsupported falls back to json", func(t *testing.T) {
+		rec := writeWith(wr, "image/png", restErr)
+		if rec.Code != restErr.Code {
+			t.Errorf("Expected status %d, got %d", restErr.Code, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != MediaTypeJSON {
+			t.Errorf("Expected JSON fallback, got '%s'", ct)
+		}
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		rec := writeWith(wr, "image/png, */*;q=0", restErr)
+		if rec.Code != http.StatusNotAcceptable {
+			t.Errorf("Expected status 406, got %d", rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != MediaTypeJSON {
+			t.Errorf("Expected JSON fallback, got '%s'", ct)
+		}
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		rec := writeWith(wr, "", errors.New("secret database failure"))
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status 500, got %d", rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "secret") {
+			t.Error("Expected the wrapped error not to be exposed")
+		}
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		rec := writeWith(wr, "", nil)
+		if rec.Body.Len() != 0 || len(rec.Header()) != 0 {
+			t.Error("Expected nothing to be written")
+		}
+	})
+}
+
+func TestWithMediaTypes(t *testing.T) {
+	wr := NewWriter(WithMediaTypes(MediaTypeProblemJSON, "application/yaml", MediaTypeText))
+
+	rec := writeWith(wr, "", NewNotFoundError("missing"))
+	if ct := rec.Header().Get("Content-Type"); ct != MediaTypeProblemJSON {
+		t.Errorf("Expected first offered type, got '%s'", ct)
+	}
+
+	rec = writeWith(wr, MediaTypeXML, NewNotFoundError("missing"))
+	if rec.Code != http.StatusNotFound || rec.Header().Get("Content-Type") != MediaTypeProblemJSON {
+		t.Errorf("Expected the first offered type for a type that is not offered, got %d '%s'", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	rec = writeWith(wr, "application/problem+json;q=0, text/plain;q=0", NewNotFoundError("missing"))
+	if rec.Code != http.StatusNotAcceptable {
+		t.Errorf("Expected 406 when every offered type is refused, got %d", rec.Code)
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	WriteError(rec, req, NewNotFoundError("missing"))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,122 @@
+package rest_err
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// ProblemXMLNamespace is the namespace of application/problem+xml documents (RFC 9457 Appendix B)
+const ProblemXMLNamespace = "urn:ietf:rfc:7807"
+
+// xmlRestErr is the XML representation of a RestErr:
+//
+//	<error>
+//	  <message>invalid request parameters</message>
+//	  <error>bad request</error>
+//	  <code>400</code>
+//	  <cause><field>email</field><message>invalid email address</message></cause>
+//	  <timestamp>2024-01-02T03:04:05Z</timestamp>
+//	</error>
+type xmlRestErr struct {
+	Message   string   `xml:"message"`
+	Err       string   `xml:"error"`
+	Code      int      `xml:"code"`
+	AppCode   string   `xml:"app_code,omitempty"`
+	Causes    []Causes `xml:"cause"`
+	Timestamp string   `xml:"timestamp,omitempty"`
+}
+
+// MarshalXML encodes the error as an <error> element, or under the parent's element name when nested.
+// It has a value receiver so errors held by value encode the same way
+func (r RestErr) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if start.Name.Local == "RestErr" {
+		start.Name = xml.Name{Local: "error"}
+	}
+	return e.EncodeElement(xmlRestErr{
+		Message:   r.Message,
+		Err:       r.Err,
+		Code:      r.Code,
+		AppCode:   r.AppCode,
+		Causes:    r.Causes,
+		Timestamp: formatXMLTime(r.Timestamp),
+	}, start)
+}
+
+// UnmarshalXML decodes an element produced by MarshalXML
+func (r *RestErr) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var x xmlRestErr
+	if err := d.DecodeElement(&x, &start); err != nil {
+		return err
+	}
+	ts, err := parseXMLTime(x.Timestamp)
+	if err != nil {
+		return err
+	}
+	*r = RestErr{Message: x.Message, Err: x.Err, Code: x.Code, AppCode: x.AppCode, Causes: x.Causes, Timestamp: ts}
+	return nil
+}
+
+// xmlProblem follows RFC 9457 Appendix B, arrays are represented by <i> elements
+type xmlProblem struct {
+	XMLName   xml.Name          `xml:"urn:ietf:rfc:7807 problem"`
+	Type      string            `xml:"type,omitempty"`
+	Title     string            `xml:"title,omitempty"`
+	Status    int               `xml:"status,omitempty"`
+	Detail    string            `xml:"detail,omitempty"`
+	Instance  string            `xml:"instance,omitempty"`
+	AppCode   string            `xml:"app_code,omitempty"`
+	Causes    *xmlProblemCauses `xml:"causes"`
+	Timestamp string            `xml:"timestamp,omitempty"`
+}
+
+type xmlProblemCauses struct {
+	Items []Causes `xml:"i"`
+}
+
+// MarshalXML encodes the problem as an application/problem+xml <problem> document
+func (p *Problem) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	x := xmlProblem{
+		Type:      p.Type,
+		Title:     p.Title,
+		Status:    p.Status,
+		Detail:    p.Detail,
+		Instance:  p.Instance,
+		AppCode:   p.AppCode,
+		Timestamp: formatXMLTime(p.Timestamp),
+	}
+	if len(p.Causes) > 0 {
+		x.Causes = &xmlProblemCauses{Items: p.Causes}
+	}
+	return e.Encode(x)
+}
+
+// UnmarshalXML decodes an application/problem+xml <problem> document
+func (p *Problem) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var x xmlProblem
+	if err := d.DecodeElement(&x, &start); err != nil {
+		return err
+	}
+	ts, err := parseXMLTime(x.Timestamp)
+	if err != nil {
+		return err
+	}
+	*p = Problem{Type: x.Type, Title: x.Title, Status: x.Status, Detail: x.Detail, Instance: x.Instance, AppCode: x.AppCode, Timestamp: ts}
+	if x.Causes != nil {
+		p.Causes = x.Causes.Items
+	}
+	return nil
+}
+
+func formatXMLTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func parseXMLTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
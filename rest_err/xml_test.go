@@ -0,0 +1,124 @@
+package rest_err
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestErr_MarshalXML(t *testing.T) {
+	restErr := NewBadRequestValidationError("invalid request", []Causes{
+		{Field: "email", Message: "is required"},
+		{Field: "limit", Message: "must be an integer", Location: LocationQuery},
+	})
+	restErr.Timestamp = time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+
+	data, err := xml.Marshal(restErr)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `<error><message>invalid request</message><error>bad request</error><code>400</code>` +
+		`<cause><field>email</field><message>is required</message></cause>` +
+		`<cause><field>limit</field><message>must be an integer</message><location>query</location></cause>` +
+		`<timestamp>2024-01-02T03:04:05Z</timestamp></error>`
+	if string(data) != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		var decoded RestErr
+		if err := xml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded.Message != restErr.Message || decoded.Err != restErr.Err || decoded.Code != restErr.Code {
+			t.Errorf("Unexpected decoded error %+v", decoded)
+		}
+		if !reflect.DeepEqual(decoded.Causes, restErr.Causes) {
+			t.Errorf("Expected causes %+v, got %+v", restErr.Causes, decoded.Causes)
+		}
+		if !decoded.Timestamp.Equal(restErr.Timestamp.Truncate(time.Second)) {
+			t.Errorf("Unexpected timestamp %v", decoded.Timestamp)
+		}
+	})
+
+	t.Run("nested element keeps its name", func(t *testing.T) {
+		data, err := xml.Marshal(struct {
+			XMLName xml.Name `xml:"response"`
+			Failure *RestErr `xml:"failure"`
+		}{Failure: NewNotFoundError("missing")})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(string(data), "<response><failure><message>missing</message>") {
+			t.Errorf("Unexpected nested XML %s", data)
+		}
+	})
+
+	t.Run("held by value", func(t *testing.T) {
+		byValue, err := xml.Marshal(*restErr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(byValue) != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, byValue)
+		}
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		var decoded RestErr
+		if err := xml.Unmarshal([]byte(`<error><timestamp>yesterday</timestamp></error>`), &decoded); err == nil {
+			t.Error("Expected error for invalid timestamp")
+		}
+	})
+}
+
+func TestProblem_MarshalXML(t *testing.T) {
+	problem := NewNotFoundError("user not found").ToProblem()
+	problem.Instance = "/users/42"
+	problem.Causes = []Causes{{Field: "id", Message: "unknown", Location: LocationPath}}
+	problem.Timestamp = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	data, err := xml.Marshal(problem)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `<problem xmlns="urn:ietf:rfc:7807"><title>Not Found</title><status>404</status>` +
+		`<detail>user not found</detail><instance>/users/42</instance>` +
+		`<causes><i><field>id</field><message>unknown</message><location>path</location></i></causes>` +
+		`<timestamp>2024-01-02T03:04:05Z</timestamp></problem>`
+	if string(data) != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+	}
+
+	var decoded Problem
+	if err := xml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, problem) {
+		t.Errorf("Expected %+v, got %+v", problem, decoded)
+	}
+}
+
+func TestWriter_ProblemXML(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("Accept", MediaTypeProblemXML)
+	rec := httptest.NewRecorder()
+	NewWriter().Write(rec, req, NewNotFoundError("user not found"))
+
+	if ct := rec.Header().Get("Content-Type"); ct != MediaTypeProblemXML {
+		t.Errorf("Expected problem+xml content type, got '%s'", ct)
+	}
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, xml.Header+`<problem xmlns="`+ProblemXMLNamespace+`">`) {
+		t.Errorf("Unexpected body %s", body)
+	}
+	if !strings.Contains(body, "<instance>/users/42</instance>") {
+		t.Errorf("Expected instance in body, got %s", body)
+	}
+}